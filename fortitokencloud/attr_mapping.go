@@ -0,0 +1,184 @@
+package fortitokencloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// attrMappingValuesKey - Private state key holding the attr_mapping values FTC returned that are not strings
+const attrMappingValuesKey = "attr_mapping_values"
+
+// attrMappingPayload converts the attr_mapping map to the JSON object FTC expects.
+func attrMappingPayload(attr_mapping types.Map) map[string]interface{} {
+	obj := map[string]interface{}{}
+	for key, value := range attr_mapping.Elements() {
+		if value, ok := value.(types.String); ok {
+			obj[key] = value.ValueString()
+		}
+	}
+	return obj
+}
+
+// attrMappingStrings flattens the attr_mapping object returned by FTC, values that are not
+// strings are kept as their JSON encoding.
+func attrMappingStrings(attr_mapping interface{}) map[string]string {
+	values := map[string]string{}
+	obj, _ := attr_mapping.(map[string]interface{})
+	for key, value := range obj {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		} else {
+			encoded, _ := json.Marshal(value)
+			values[key] = string(encoded)
+		}
+	}
+	return values
+}
+
+// recordAttrMapping keeps the JSON encoding of the attr_mapping values returned by FTC that are
+// not strings in private state, so restoreAttrMappingTypes can send them back with their type.
+func recordAttrMapping(ctx context.Context, private privateStateSetter, attr_mapping interface{}) diag.Diagnostics {
+	values := map[string]json.RawMessage{}
+	obj, _ := attr_mapping.(map[string]interface{})
+	for key, value := range obj {
+		if _, ok := value.(string); ok {
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			var diags diag.Diagnostics
+			diags.AddError("Unable to record attr_mapping", err.Error())
+			return diags
+		}
+		values[key] = encoded
+	}
+	if len(values) == 0 {
+		// an empty value removes the key
+		return private.SetKey(ctx, attrMappingValuesKey, nil)
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to record attr_mapping", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, attrMappingValuesKey, data)
+}
+
+// restoreAttrMappingTypes replaces the attr_mapping values in obj that still hold the JSON
+// encoding of a value recorded by recordAttrMapping with that value, so an update does not turn
+// FTC's numbers, booleans, lists or objects into strings. Values changed in the configuration
+// are sent as strings.
+func restoreAttrMappingTypes(ctx context.Context, private privateStateGetter, obj *map[string]interface{}) diag.Diagnostics {
+	payload, _ := (*obj)["attr_mapping"].(map[string]interface{})
+	if len(payload) == 0 {
+		return nil
+	}
+	data, diags := private.GetKey(ctx, attrMappingValuesKey)
+	if diags.HasError() || len(data) == 0 {
+		return diags
+	}
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		diags.AddError("Invalid private state", "Could not decode the attr_mapping values: "+err.Error())
+		return diags
+	}
+	for key, encoded := range values {
+		if value, ok := payload[key].(string); ok && value == string(encoded) {
+			payload[key] = encoded
+		}
+	}
+	return diags
+}
+
+// attrMappingValue converts the attr_mapping object returned by FTC to a map attribute value.
+func attrMappingValue(attr_mapping interface{}) types.Map {
+	elements := map[string]attr.Value{}
+	for key, value := range attrMappingStrings(attr_mapping) {
+		elements[key] = types.StringValue(value)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// attrMappingPriorSchema returns a copy of current storing attr_mapping as a JSON string, the
+// form used before attr_mapping became a map.
+func attrMappingPriorSchema(current schema.Schema) schema.Schema {
+	prior := schema.Schema{Attributes: map[string]schema.Attribute{}, Blocks: current.Blocks}
+	for name, attribute := range current.Attributes {
+		prior.Attributes[name] = attribute
+	}
+	prior.Attributes["attr_mapping"] = schema.StringAttribute{
+		Optional: true,
+		Computed: true,
+	}
+	return prior
+}
+
+// upgradeAttrMappingValue replaces the JSON string attr_mapping in the raw state values with
+// the equivalent map.
+func upgradeAttrMappingValue(values map[string]tftypes.Value) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var attr_mapping string
+	if err := values["attr_mapping"].As(&attr_mapping); err != nil {
+		diags.AddError("Unable to upgrade state", err.Error())
+		return diags
+	}
+	var decoded interface{}
+	if attr_mapping != "" {
+		if err := json.Unmarshal([]byte(attr_mapping), &decoded); err != nil {
+			diags.AddAttributeError(
+				path.Root("attr_mapping"),
+				"Unable to upgrade state",
+				fmt.Sprintf("attr_mapping in state is not a JSON object: %s", err),
+			)
+			return diags
+		}
+	}
+	if _, ok := decoded.(map[string]interface{}); decoded != nil && !ok {
+		diags.AddAttributeError(
+			path.Root("attr_mapping"),
+			"Unable to upgrade state",
+			fmt.Sprintf("attr_mapping in state is not a JSON object: %s", attr_mapping),
+		)
+		return diags
+	}
+	mapping := map[string]tftypes.Value{}
+	for key, value := range attrMappingStrings(decoded) {
+		mapping[key] = tftypes.NewValue(tftypes.String, value)
+	}
+	values["attr_mapping"] = tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, mapping)
+	return diags
+}
+
+// upgradeAttrMapping returns the state upgrader from a schema storing attr_mapping as a JSON
+// string. current is the schema the state is upgraded to, every other attribute is unchanged.
+func upgradeAttrMapping(current schema.Schema) resource.StateUpgrader {
+	prior := attrMappingPriorSchema(current)
+
+	return resource.StateUpgrader{
+		PriorSchema: &prior,
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			values := map[string]tftypes.Value{}
+			if err := req.State.Raw.As(&values); err != nil {
+				resp.Diagnostics.AddError("Unable to upgrade state", err.Error())
+				return
+			}
+
+			resp.Diagnostics.Append(upgradeAttrMappingValue(values)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			resp.State.Raw = tftypes.NewValue(resp.State.Schema.Type().TerraformType(ctx), values)
+		},
+	}
+}
@@ -0,0 +1,241 @@
+package fortitokencloud
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testUpgradeState upgrades raw JSON state written with schema version through the provider
+// server, the way Terraform does when it loads the state of an older provider.
+func testUpgradeState(t *testing.T, type_name string, version int64, raw string) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	t.Helper()
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: type_name,
+		Version:  version,
+		RawState: &tfprotov6.RawState{JSON: []byte(raw)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.UpgradedState == nil {
+		return tftypes.Value{}, resp.Diagnostics
+	}
+	state, err := resp.UpgradedState.Unmarshal(schemas.ResourceSchemas[type_name].ValueType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state, resp.Diagnostics
+}
+
+// testStateValue returns the value at the attribute path given by names.
+func testStateValue(t *testing.T, state tftypes.Value, names ...string) tftypes.Value {
+	t.Helper()
+	attribute_path := tftypes.NewAttributePath()
+	for _, name := range names {
+		attribute_path = attribute_path.WithAttributeName(name)
+	}
+	value, _, err := tftypes.WalkAttributePath(state, attribute_path)
+	if err != nil {
+		t.Fatalf("%s: %s", attribute_path, err)
+	}
+	return value.(tftypes.Value)
+}
+
+// testStateString returns the string at the attribute path given by names, "<null>" when it is null.
+func testStateString(t *testing.T, state tftypes.Value, names ...string) string {
+	t.Helper()
+	value := testStateValue(t, state, names...)
+	if value.IsNull() {
+		return "<null>"
+	}
+	var s string
+	if err := value.As(&s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// testStateMap returns the string map at the attribute path given by names.
+func testStateMap(t *testing.T, state tftypes.Value, names ...string) map[string]string {
+	t.Helper()
+	values := map[string]tftypes.Value{}
+	if err := testStateValue(t, state, names...).As(&values); err != nil {
+		t.Fatal(err)
+	}
+	mapping := map[string]string{}
+	for key, value := range values {
+		var s string
+		if err := value.As(&s); err != nil {
+			t.Fatal(err)
+		}
+		mapping[key] = s
+	}
+	return mapping
+}
+
+func testNoErrors(t *testing.T, diags []*tfprotov6.Diagnostic) {
+	t.Helper()
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+}
+
+func testHasError(t *testing.T, diags []*tfprotov6.Diagnostic) {
+	t.Helper()
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			return
+		}
+	}
+	t.Fatal("got no error diagnostic")
+}
+
+func TestAttrMappingPayload(t *testing.T) {
+	attr_mapping := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"username": types.StringValue("Username"),
+		"groups":   types.StringValue(`["admins"]`),
+	})
+	want := map[string]interface{}{"username": "Username", "groups": `["admins"]`}
+	if got := attrMappingPayload(attr_mapping); !reflect.DeepEqual(got, want) {
+		t.Errorf("attrMappingPayload = %#v, want %#v", got, want)
+	}
+	if got := attrMappingPayload(types.MapNull(types.StringType)); len(got) != 0 || got == nil {
+		t.Errorf("attrMappingPayload of a null map = %#v, want an empty object", got)
+	}
+}
+
+func TestAttrMappingValue(t *testing.T) {
+	tests := []struct {
+		name         string
+		attr_mapping interface{}
+		want         map[string]string
+	}{
+		{name: "null", attr_mapping: nil, want: map[string]string{}},
+		{name: "not an object", attr_mapping: []interface{}{"username"}, want: map[string]string{}},
+		{
+			name:         "non-string values",
+			attr_mapping: map[string]interface{}{"username": "Username", "ttl": float64(1), "groups": []interface{}{"a"}, "extra": nil},
+			want:         map[string]string{"username": "Username", "ttl": "1", "groups": `["a"]`, "extra": "null"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attrMappingStrings(tt.attr_mapping); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("attrMappingStrings = %#v, want %#v", got, tt.want)
+			}
+			value := attrMappingValue(tt.attr_mapping)
+			if value.IsNull() || len(value.Elements()) != len(tt.want) {
+				t.Errorf("attrMappingValue = %v, want %d elements", value, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestApplicationUpgradeStateV0(t *testing.T) {
+	tests := []struct {
+		name         string
+		attr_mapping string
+		want         map[string]string
+	}{
+		{
+			name:         "strings",
+			attr_mapping: `{\"username\": \"Username\"}`,
+			want:         map[string]string{"username": "Username"},
+		},
+		{
+			name:         "non-string values",
+			attr_mapping: `{\"username\": \"Username\", \"ttl\": 1, \"groups\": [\"a\"]}`,
+			want:         map[string]string{"username": "Username", "ttl": "1", "groups": `["a"]`},
+		},
+		{
+			name:         "empty",
+			attr_mapping: ``,
+			want:         map[string]string{},
+		},
+		{
+			name:         "null",
+			attr_mapping: `null`,
+			want:         map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, diags := testUpgradeState(t, "fortitokencloud_application", 0, `{
+				"id": "app-1", "name": "fgt_sslvpn", "realm_id": "realm-1", "ttl": 900,
+				"sp_acs_url": "https://sp.example.com/saml/login", "user_source_ids": ["us-1"],
+				"attr_mapping": "`+tt.attr_mapping+`"
+			}`)
+			testNoErrors(t, diags)
+
+			if mapping := testStateMap(t, state, "attr_mapping"); !reflect.DeepEqual(mapping, tt.want) {
+				t.Errorf("attr_mapping = %v, want %v", mapping, tt.want)
+			}
+			if got := testStateString(t, state, "sp_acs_url"); got != "https://sp.example.com/saml/login" {
+				t.Errorf("sp_acs_url = %q, want it kept", got)
+			}
+		})
+	}
+}
+
+func TestUpgradeStateV0InvalidAttrMapping(t *testing.T) {
+	for _, type_name := range []string{"fortitokencloud_application", "fortitokencloud_usersource"} {
+		t.Run(type_name, func(t *testing.T) {
+			_, diags := testUpgradeState(t, type_name, 0, `{"id": "id-1", "name": "test", "attr_mapping": "{\"username\""}`)
+			testHasError(t, diags)
+		})
+		// JSON that is not an object is not dropped silently
+		t.Run(type_name+" not an object", func(t *testing.T) {
+			_, diags := testUpgradeState(t, type_name, 0, `{"id": "id-1", "name": "test", "attr_mapping": "[\"username\"]"}`)
+			testHasError(t, diags)
+		})
+	}
+}
+
+func TestRestoreAttrMappingTypes(t *testing.T) {
+	ctx := context.Background()
+	private := testPrivateState{}
+	returned := map[string]interface{}{"username": "Username", "ttl": float64(1), "groups": []interface{}{"a"}}
+	if diags := recordAttrMapping(ctx, private, returned); diags.HasError() {
+		t.Fatalf("recordAttrMapping: %v", diags)
+	}
+
+	// unchanged values go back with their type, changed ones as the configured string
+	obj := map[string]interface{}{"attr_mapping": map[string]interface{}{"username": "Username", "ttl": "1", "groups": `["b"]`}}
+	if diags := restoreAttrMappingTypes(ctx, private, &obj); diags.HasError() {
+		t.Fatalf("restoreAttrMappingTypes: %v", diags)
+	}
+	got, err := json.Marshal(obj["attr_mapping"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"groups":"[\"b\"]","ttl":1,"username":"Username"}`; string(got) != want {
+		t.Errorf("attr_mapping payload = %s, want %s", got, want)
+	}
+
+	// only strings returned clears the record
+	if diags := recordAttrMapping(ctx, private, map[string]interface{}{"username": "Username"}); diags.HasError() {
+		t.Fatalf("recordAttrMapping: %v", diags)
+	}
+	if len(private[attrMappingValuesKey]) != 0 {
+		t.Error("attr_mapping values still recorded after FTC returned only strings")
+	}
+}
@@ -0,0 +1,61 @@
+package fortitokencloud
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// maxLogoSize is the largest logo FortiToken Cloud accepts for a login page.
+const maxLogoSize = 512 * 1024
+
+// hexColorPattern matches the #RRGGBB colors used by branding.
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// readLogo loads a logo file and checks it is a PNG, JPEG, GIF or SVG image FTC accepts.
+func readLogo(path string) ([]byte, error) {
+	logo, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(logo) == 0 {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+	if len(logo) > maxLogoSize {
+		return nil, fmt.Errorf("%s is %d bytes, logos must not be larger than %d bytes", path, len(logo), maxLogoSize)
+	}
+	switch http.DetectContentType(logo) {
+	case "image/png", "image/jpeg", "image/gif":
+		return logo, nil
+	}
+	// SVG is sniffed as XML or text, look for the root element instead
+	if strings.HasSuffix(strings.ToLower(path), ".svg") && bytes.Contains(logo, []byte("<svg")) {
+		return logo, nil
+	}
+	return nil, fmt.Errorf("%s is not a PNG, JPEG, GIF or SVG image", path)
+}
+
+// hashLogo returns the hex SHA-256 digest of the logo content, empty when there is no logo.
+func hashLogo(logo []byte) string {
+	if len(logo) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(logo)
+	return hex.EncodeToString(sum[:])
+}
+
+// brandingLogoHash hashes the base64 logo FTC returns, so logos replaced in the portal show up
+// as a change.
+func brandingLogoHash(logo string) string {
+	content, err := base64.StdEncoding.DecodeString(logo)
+	if err != nil {
+		content = []byte(logo)
+	}
+	return hashLogo(content)
+}
@@ -0,0 +1,80 @@
+package fortitokencloud
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testLogo writes a PNG of the given width to a temporary file and returns its path and content.
+func testLogo(t *testing.T, width int) (string, []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, 1))); err != nil {
+		t.Fatal(err)
+	}
+	logo_path := filepath.Join(t.TempDir(), "logo.png")
+	if err := os.WriteFile(logo_path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return logo_path, buf.Bytes()
+}
+
+func TestReadLogo(t *testing.T) {
+	logo_path, content := testLogo(t, 4)
+	logo, err := readLogo(logo_path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(logo, content) {
+		t.Error("readLogo did not return the file content")
+	}
+
+	svg_path := filepath.Join(t.TempDir(), "logo.svg")
+	os.WriteFile(svg_path, []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"/>`), 0o600)
+	if _, err := readLogo(svg_path); err != nil {
+		t.Errorf("svg logo: %s", err)
+	}
+}
+
+func TestReadLogoErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"empty.png": {},
+		"large.png": append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, maxLogoSize)...),
+		"text.png":  []byte("not an image"),
+		"text.svg":  []byte("not an image"),
+	}
+	cases := map[string]string{
+		"empty.png":   "is empty",
+		"large.png":   "must not be larger than",
+		"text.png":    "is not a PNG, JPEG, GIF or SVG image",
+		"text.svg":    "is not a PNG, JPEG, GIF or SVG image",
+		"missing.png": "no such file",
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(dir, name), content, 0o600)
+	}
+	for name, want := range cases {
+		_, err := readLogo(filepath.Join(dir, name))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got %v, want an error containing %q", name, err, want)
+		}
+	}
+}
+
+func TestBrandingLogoHash(t *testing.T) {
+	if hashLogo(nil) != "" || brandingLogoHash("") != "" {
+		t.Error("a missing logo should hash to an empty string")
+	}
+	_, content := testLogo(t, 1)
+	// FTC returns the uploaded content base64 encoded
+	if got := brandingLogoHash(base64.StdEncoding.EncodeToString(content)); got != hashLogo(content) {
+		t.Errorf("brandingLogoHash = %s, want %s", got, hashLogo(content))
+	}
+}
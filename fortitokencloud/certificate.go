@@ -0,0 +1,169 @@
+package fortitokencloud
+
+import (
+	"crypto"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultCertExpiryWarningDays is used when cert_expiry_warning_days is not configured.
+const defaultCertExpiryWarningDays = 30
+
+// parseCertificatePEM decodes the first CERTIFICATE block of a PEM string.
+func parseCertificatePEM(data string) (*x509.Certificate, error) {
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM encoded certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// parsePrivateKeyPEM decodes the first private key block of a PEM string, in PKCS #8, PKCS #1
+// or SEC 1 form.
+func parsePrivateKeyPEM(data string) (crypto.Signer, error) {
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM encoded private key found")
+		}
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+}
+
+// keyMatchesCertificate reports whether key is the private key of cert.
+func keyMatchesCertificate(cert *x509.Certificate, key crypto.Signer) bool {
+	public, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && public.Equal(key.Public())
+}
+
+// formatSerial formats the certificate serial number as colon separated hex, like fingerprints.
+func formatSerial(cert *x509.Certificate) string {
+	return formatFingerprint(cert.SerialNumber.Bytes())
+}
+
+// fingerprintSHA1 formats the SHA-1 digest of the DER certificate as colon separated hex,
+// the thumbprint form many SPs still ask for.
+func fingerprintSHA1(cert *x509.Certificate) string {
+	sum := sha1.Sum(cert.Raw)
+	return formatFingerprint(sum[:])
+}
+
+// fingerprintSHA256 formats the SHA-256 digest of the DER certificate as colon separated hex.
+func fingerprintSHA256(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return formatFingerprint(sum[:])
+}
+
+func formatFingerprint(sum []byte) string {
+	parts := make([]string, 0, len(sum))
+	for _, b := range sum {
+		parts = append(parts, fmt.Sprintf("%02X", b))
+	}
+	return strings.Join(parts, ":")
+}
+
+// certificateToPEMString encodes a parsed certificate as PEM.
+func certificateToPEMString(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+// formatExpiry renders the certificate expiry in the RFC 3339 form used for state.
+func formatExpiry(cert *x509.Certificate) string {
+	return cert.NotAfter.UTC().Format(time.RFC3339)
+}
+
+// formatNotBefore renders the start of the certificate validity in RFC 3339 form.
+func formatNotBefore(cert *x509.Certificate) string {
+	return cert.NotBefore.UTC().Format(time.RFC3339)
+}
+
+// certificateDetails returns the SHA-256 fingerprint and expiry of a PEM certificate,
+// both empty when there is no certificate or it cannot be parsed.
+func certificateDetails(data string) (types.String, types.String) {
+	if data == "" {
+		return types.StringValue(""), types.StringValue("")
+	}
+	cert, err := parseCertificatePEM(data)
+	if err != nil {
+		return types.StringValue(""), types.StringValue("")
+	}
+	return types.StringValue(fingerprintSHA256(cert)), types.StringValue(formatExpiry(cert))
+}
+
+// checkCertificateExpiry fails the plan on an expired certificate and warns about one
+// expiring within warning_days. An expired certificate that is not configured, such as one
+// kept from state, only warns so the plan that rotates or replaces it can still run. Empty or
+// unparsable certificates are left to validation.
+func checkCertificateExpiry(data string, attribute path.Path, warning_days int64, configured bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if data == "" {
+		return diags
+	}
+	cert, err := parseCertificatePEM(data)
+	if err != nil {
+		return diags
+	}
+
+	now := time.Now()
+	if !now.Before(cert.NotAfter) {
+		if !configured {
+			diags.AddAttributeWarning(
+				attribute,
+				"Certificate expired",
+				fmt.Sprintf("The certificate %q expired on %s. SAML trust based on it no longer works, rotate or replace it.", cert.Subject.String(), formatExpiry(cert)),
+			)
+			return diags
+		}
+		diags.AddAttributeError(
+			attribute,
+			"Certificate expired",
+			fmt.Sprintf("The certificate %q expired on %s. SAML trust based on it no longer works, replace it with a valid certificate.", cert.Subject.String(), formatExpiry(cert)),
+		)
+		return diags
+	}
+	if warning_days > 0 && cert.NotAfter.Before(now.AddDate(0, 0, int(warning_days))) {
+		diags.AddAttributeWarning(
+			attribute,
+			"Certificate expires soon",
+			fmt.Sprintf("The certificate %q expires on %s, in %d days. Rotate it before SAML trust based on it stops working.", cert.Subject.String(), formatExpiry(cert), int(math.Ceil(cert.NotAfter.Sub(now).Hours()/24))),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,199 @@
+package fortitokencloud
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// testKeyPair returns a self-signed PEM certificate for commonName expiring at notAfter and
+// its PKCS #8 private key.
+func testKeyPair(t *testing.T, commonName string, notAfter time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key_der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key_der}))
+}
+
+func TestParseCertificatePEM(t *testing.T) {
+	der := testCertificate(t, "idp.example.com", time.Now().Add(time.Hour))
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	// a key ahead of the certificate is skipped
+	bundle := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})) + certPEM
+	for _, data := range []string{certPEM, bundle} {
+		cert, err := parseCertificatePEM(data)
+		if err != nil {
+			t.Fatalf("parseCertificatePEM: %v", err)
+		}
+		if cert.Subject.CommonName != "idp.example.com" {
+			t.Errorf("parsed certificate for %q, want idp.example.com", cert.Subject.CommonName)
+		}
+	}
+
+	for _, data := range []string{"", "not PEM", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("bad")}))} {
+		if _, err := parseCertificatePEM(data); err == nil {
+			t.Errorf("parseCertificatePEM(%q) succeeded", data)
+		}
+	}
+}
+
+func TestCertificateDetails(t *testing.T) {
+	notAfter := time.Date(2031, 5, 4, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	der := testCertificate(t, "idp.example.com", notAfter)
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	fingerprint, expiry := certificateDetails(certPEM)
+	sum := sha256.Sum256(der)
+	hex := strings.ToUpper(fmt.Sprintf("%x", sum))
+	want := []string{}
+	for i := 0; i < len(hex); i += 2 {
+		want = append(want, hex[i:i+2])
+	}
+	if fingerprint.ValueString() != strings.Join(want, ":") {
+		t.Errorf("fingerprint = %q, want %q", fingerprint.ValueString(), strings.Join(want, ":"))
+	}
+	if expiry.ValueString() != "2031-05-04T10:30:00Z" {
+		t.Errorf("expiry = %q, want 2031-05-04T10:30:00Z", expiry.ValueString())
+	}
+
+	for _, data := range []string{"", "not PEM"} {
+		fingerprint, expiry := certificateDetails(data)
+		if fingerprint.ValueString() != "" || expiry.ValueString() != "" || fingerprint.IsNull() || expiry.IsNull() {
+			t.Errorf("certificateDetails(%q) = %v, %v, want empty strings", data, fingerprint, expiry)
+		}
+	}
+}
+
+func TestFormatFingerprint(t *testing.T) {
+	if got := formatFingerprint([]byte{0x0a, 0xff, 0x10}); got != "0A:FF:10" {
+		t.Errorf("formatFingerprint = %q, want 0A:FF:10", got)
+	}
+}
+
+func TestFingerprintSHA1(t *testing.T) {
+	der := testCertificate(t, "idp.example.com", time.Now().Add(time.Hour))
+	cert, err := parseCertificatePEM(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha1.Sum(der)
+	if got, want := fingerprintSHA1(cert), formatFingerprint(sum[:]); got != want || len(got) != 20*3-1 {
+		t.Errorf("fingerprintSHA1 = %q, want %q", got, want)
+	}
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	cert_pem, key_pem := testKeyPair(t, "idp.example.com", time.Now().Add(time.Hour))
+	cert, err := parseCertificatePEM(cert_pem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the key may follow the certificate in a bundle
+	key, err := parsePrivateKeyPEM(cert_pem + key_pem)
+	if err != nil {
+		t.Fatalf("parsePrivateKeyPEM: %v", err)
+	}
+	if !keyMatchesCertificate(cert, key) {
+		t.Error("the certificate's own key does not match it")
+	}
+
+	rsa_key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsa_pem := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsa_key)}))
+	other, err := parsePrivateKeyPEM(rsa_pem)
+	if err != nil {
+		t.Fatalf("parsePrivateKeyPEM(PKCS #1): %v", err)
+	}
+	if keyMatchesCertificate(cert, other) {
+		t.Error("another key matches the certificate")
+	}
+
+	for _, data := range []string{"", cert_pem, string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("bad")}))} {
+		if _, err := parsePrivateKeyPEM(data); err == nil {
+			t.Errorf("parsePrivateKeyPEM(%q) succeeded", data)
+		}
+	}
+}
+
+func TestFormatSerial(t *testing.T) {
+	cert := &x509.Certificate{SerialNumber: big.NewInt(0x0aff10)}
+	if got := formatSerial(cert); got != "0A:FF:10" {
+		t.Errorf("formatSerial = %q, want 0A:FF:10", got)
+	}
+}
+
+func TestCheckCertificateExpiry(t *testing.T) {
+	pemFor := func(notAfter time.Time) string {
+		der := testCertificate(t, "idp.example.com", notAfter)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+	valid := pemFor(time.Now().Add(90 * 24 * time.Hour))
+	expiring := pemFor(time.Now().Add(10 * 24 * time.Hour))
+	expired := pemFor(time.Now().Add(-time.Hour))
+
+	cases := []struct {
+		name         string
+		data         string
+		warning_days int64
+		configured   bool
+		errors       int
+		warnings     int
+		summary      string
+	}{
+		{"valid", valid, 30, true, 0, 0, ""},
+		{"expiring", expiring, 30, true, 0, 1, "Certificate expires soon"},
+		{"expiring outside the window", expiring, 5, true, 0, 0, ""},
+		{"warnings disabled", expiring, 0, true, 0, 0, ""},
+		{"expired", expired, 30, true, 1, 0, "Certificate expired"},
+		{"expired with warnings disabled", expired, 0, true, 1, 0, "Certificate expired"},
+		{"expired kept from state", expired, 30, false, 0, 1, "Certificate expired"},
+		{"expiring kept from state", expiring, 30, false, 0, 1, "Certificate expires soon"},
+		{"empty", "", 30, true, 0, 0, ""},
+		{"invalid", "not PEM", 30, true, 0, 0, ""},
+	}
+	for _, c := range cases {
+		diags := checkCertificateExpiry(c.data, path.Root("signing_cert"), c.warning_days, c.configured)
+		if diags.ErrorsCount() != c.errors || diags.WarningsCount() != c.warnings {
+			t.Errorf("%s: got %d errors and %d warnings, want %d and %d", c.name, diags.ErrorsCount(), diags.WarningsCount(), c.errors, c.warnings)
+			continue
+		}
+		if c.summary != "" && diags[0].Summary() != c.summary {
+			t.Errorf("%s: summary %q, want %q", c.name, diags[0].Summary(), c.summary)
+		}
+	}
+}
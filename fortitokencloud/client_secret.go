@@ -0,0 +1,67 @@
+package fortitokencloud
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// clientSecretHashKey - Private state key holding the hash of the client secret last sent
+const clientSecretHashKey = "client_secret_hash"
+
+// privateStateGetter is the read side of the framework private state.
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// privateStateSetter is the write side of the framework private state.
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// hashClientSecret returns the hex encoded SHA-256 digest of secret.
+func hashClientSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// clientSecretReturned reports whether FTC returned the client secret itself, not an empty or
+// masked placeholder such as "********" that cannot be compared with the secret sent.
+func clientSecretReturned(secret string) bool {
+	return strings.Trim(secret, "*•") != ""
+}
+
+// clientSecretHash returns the hash of the client secret last sent, empty when none is recorded.
+func clientSecretHash(ctx context.Context, private privateStateGetter) (string, diag.Diagnostics) {
+	data, diags := private.GetKey(ctx, clientSecretHashKey)
+	if diags.HasError() || len(data) == 0 {
+		return "", diags
+	}
+	var hash string
+	if err := json.Unmarshal(data, &hash); err != nil {
+		diags.AddError("Invalid private state", "Could not decode the client secret hash: "+err.Error())
+	}
+	return hash, diags
+}
+
+// recordClientSecret keeps the hash of the client secret sent in obj in private state, so a
+// secret rotated outside Terraform can be detected. Requests without a secret leave it as is.
+func recordClientSecret(ctx context.Context, private privateStateSetter, obj *map[string]interface{}) diag.Diagnostics {
+	oidc, _ := (*obj)["oidc_params"].(map[string]interface{})
+	secret, ok := oidc["client_secret"]
+	if !ok {
+		return nil
+	}
+	value, _ := secret.(string)
+	data, err := json.Marshal(hashClientSecret(value))
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to record client secret", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, clientSecretHashKey, data)
+}
@@ -0,0 +1,82 @@
+package fortitokencloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// testPrivateState is an in-memory stand-in for the framework private state.
+type testPrivateState map[string][]byte
+
+func (p testPrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func (p testPrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	p[key] = value
+	return nil
+}
+
+func TestRecordClientSecret(t *testing.T) {
+	ctx := context.Background()
+	private := testPrivateState{}
+
+	if hash, diags := clientSecretHash(ctx, private); hash != "" || diags.HasError() {
+		t.Fatalf("clientSecretHash without a recorded secret = %q, %v", hash, diags)
+	}
+
+	obj := map[string]interface{}{"oidc_params": map[string]interface{}{"client_secret": "s1"}}
+	if diags := recordClientSecret(ctx, private, &obj); diags.HasError() {
+		t.Fatalf("recordClientSecret: %v", diags)
+	}
+	hash, diags := clientSecretHash(ctx, private)
+	if diags.HasError() || hash != hashClientSecret("s1") {
+		t.Fatalf("clientSecretHash = %q, %v, want the hash of s1", hash, diags)
+	}
+	if hash == "s1" || hash == hashClientSecret("s2") {
+		t.Errorf("hash %q does not identify the secret", hash)
+	}
+
+	// requests without a secret, SAML or an unchanged write-only secret, keep the recorded hash
+	for _, obj := range []map[string]interface{}{
+		{"saml_params": map[string]interface{}{"entity_id": "idp"}},
+		{"oidc_params": map[string]interface{}{"client_id": "client"}},
+	} {
+		if diags := recordClientSecret(ctx, private, &obj); diags.HasError() {
+			t.Fatalf("recordClientSecret: %v", diags)
+		}
+		if hash, _ := clientSecretHash(ctx, private); hash != hashClientSecret("s1") {
+			t.Errorf("request %v replaced the recorded hash", obj)
+		}
+	}
+
+	// a cleared secret is recorded as the empty secret
+	obj = map[string]interface{}{"oidc_params": map[string]interface{}{"client_secret": nil}}
+	recordClientSecret(ctx, private, &obj)
+	if hash, _ := clientSecretHash(ctx, private); hash != hashClientSecret("") {
+		t.Errorf("clientSecretHash after clearing = %q, want the hash of the empty secret", hash)
+	}
+}
+
+func TestClientSecretHashInvalid(t *testing.T) {
+	private := testPrivateState{clientSecretHashKey: []byte("not JSON")}
+	if _, diags := clientSecretHash(context.Background(), private); !diags.HasError() {
+		t.Error("clientSecretHash of invalid private state returned no error")
+	}
+}
+
+func TestClientSecretReturned(t *testing.T) {
+	for secret, want := range map[string]bool{
+		"":         false,
+		"********": false,
+		"••••••":   false,
+		"s3cr*t":   true,
+		"secret":   true,
+	} {
+		if got := clientSecretReturned(secret); got != want {
+			t.Errorf("clientSecretReturned(%q) = %v, want %v", secret, got, want)
+		}
+	}
+}
@@ -29,8 +29,14 @@ func (d *applicationsDataSource) Metadata(_ context.Context, req datasource.Meta
 func (d *applicationsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 
 	var state applicationsDataSourceModel
-	apps, err := d.client.GetApplications()
-	if err != nil {
+	var apps []ftc_client.Application
+
+	// Walk every page, FTC caps the number of records returned per request
+	pager := d.client.NewApplicationsPager(ftc_client.ListOptions{})
+	for pager.Next(ctx) {
+		apps = append(apps, pager.Page()...)
+	}
+	if err := pager.Err(); err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Read FTC Applications",
 			err.Error(),
@@ -39,7 +45,7 @@ func (d *applicationsDataSource) Read(ctx context.Context, req datasource.ReadRe
 	}
 
 	// Map response body to model
-	for _, app := range apps.Apps {
+	for _, app := range apps {
 		appState := applicationModel{
 			ID:            types.StringValue(app.ID),
 			Name:          types.StringValue(app.Name),
@@ -0,0 +1,215 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &applicationIdPMetadataDataSource{}
+	_ datasource.DataSourceWithConfigure = &applicationIdPMetadataDataSource{}
+)
+
+func NewApplicationIdPMetadataDataSource() datasource.DataSource {
+	return &applicationIdPMetadataDataSource{}
+}
+
+func (d *applicationIdPMetadataDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_application_idp_metadata"
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *applicationIdPMetadataDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	var data applicationIdPMetadataDataSourceModel
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	application, err := d.client.GetApplicationWithContext(ctx, data.ApplicationID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read application",
+			err.Error(),
+		)
+		return
+	}
+	if application.OidcParams != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("application_id"),
+			"Unsupported application type",
+			"Application "+application.ID+" is an OIDC application, use its oidc.discovery_url instead of SAML IdP metadata.",
+		)
+		return
+	}
+
+	// without an explicit certificate, use the application's signing certificate or the one
+	// FTC publishes at the entity ID
+	signing_cert := data.SigningCert.ValueString()
+	if signing_cert == "" && application.SigningCertID != "" {
+		signing_certificate, err := d.client.GetSigningCertificateWithContext(ctx, application.SigningCertID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to read signing certificate",
+				"Could not read signing certificate "+application.SigningCertID+" of application "+application.ID+": "+err.Error(),
+			)
+			return
+		}
+		signing_cert = signing_certificate.Certificate
+	}
+	if signing_cert == "" {
+		published, err := loadMetadata(ctx, d.client, types.StringNull(), types.StringValue(application.EntityID))
+		if err == nil {
+			var metadata *idpMetadata
+			if metadata, err = parseIdPMetadata(published); err == nil {
+				signing_cert = metadata.signingCert()
+			}
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to read published IdP metadata",
+				"Could not read the IdP signing certificate of application "+application.ID+", set signing_cert instead: "+err.Error(),
+			)
+			return
+		}
+		if signing_cert == "" {
+			resp.Diagnostics.AddError(
+				"Unable to read published IdP metadata",
+				"The IdP metadata published at "+application.EntityID+" has no signing certificate, set signing_cert instead.",
+			)
+			return
+		}
+	}
+
+	cert, err := parseCertificatePEM(signing_cert)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("signing_cert"),
+			"Invalid signing certificate",
+			"Could not parse signing_cert as a PEM encoded X.509 certificate: "+err.Error(),
+		)
+		return
+	}
+
+	var name_id_formats []string
+	if application.SpNameID != "" {
+		name_id_formats = []string{application.SpNameID}
+	}
+	metadata_xml, err := generateIdPMetadata(application.EntityID, application.SsoUrl, application.SloUrl, cert, name_id_formats)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to generate IdP metadata",
+			err.Error(),
+		)
+		return
+	}
+
+	if data.SigningCert.IsNull() {
+		data.SigningCert = types.StringValue(certificateToPEMString(cert))
+	}
+	data = applicationIdPMetadataDataSourceModel{
+		ApplicationID:     types.StringValue(application.ID),
+		EntityID:          types.StringValue(application.EntityID),
+		SsoUrl:            types.StringValue(application.SsoUrl),
+		SloUrl:            types.StringValue(application.SloUrl),
+		SigningCert:       data.SigningCert,
+		FingerprintSHA1:   types.StringValue(fingerprintSHA1(cert)),
+		FingerprintSHA256: types.StringValue(fingerprintSHA256(cert)),
+		SigningCertExpiry: types.StringValue(formatExpiry(cert)),
+		MetadataXML:       types.StringValue(metadata_xml),
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// applicationIdPMetadataDataSource is the data source implementation.
+type applicationIdPMetadataDataSource struct {
+	client *ftc_client.Client
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *applicationIdPMetadataDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ftc_client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ftc_client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// Schema defines the schema for the data source.
+func (d *applicationIdPMetadataDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"application_id": schema.StringAttribute{
+				Required: true,
+			},
+			"signing_cert": schema.StringAttribute{
+				Description: "PEM encoded IdP signing certificate to advertise. Taken from the application's signing_cert_id, or read from the metadata FTC publishes at entity_id, when not set.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"entity_id": schema.StringAttribute{
+				Computed: true,
+			},
+			"sso_url": schema.StringAttribute{
+				Computed: true,
+			},
+			"slo_url": schema.StringAttribute{
+				Computed: true,
+			},
+			"fingerprint_sha1": schema.StringAttribute{
+				Description: "SHA-1 fingerprint of signing_cert.",
+				Computed:    true,
+			},
+			"fingerprint_sha256": schema.StringAttribute{
+				Description: "SHA-256 fingerprint of signing_cert.",
+				Computed:    true,
+			},
+			"signing_cert_expiry": schema.StringAttribute{
+				Description: "Expiry of signing_cert in RFC 3339 format.",
+				Computed:    true,
+			},
+			"metadata_xml": schema.StringAttribute{
+				Description: "SAML 2.0 IdP EntityDescriptor for the application.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// applicationIdPMetadataDataSourceModel maps the data source schema data.
+type applicationIdPMetadataDataSourceModel struct {
+	ApplicationID     types.String `tfsdk:"application_id"`
+	EntityID          types.String `tfsdk:"entity_id"`
+	SsoUrl            types.String `tfsdk:"sso_url"`
+	SloUrl            types.String `tfsdk:"slo_url"`
+	SigningCert       types.String `tfsdk:"signing_cert"`
+	FingerprintSHA1   types.String `tfsdk:"fingerprint_sha1"`
+	FingerprintSHA256 types.String `tfsdk:"fingerprint_sha256"`
+	SigningCertExpiry types.String `tfsdk:"signing_cert_expiry"`
+	MetadataXML       types.String `tfsdk:"metadata_xml"`
+}
@@ -0,0 +1,107 @@
+package fortitokencloud
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccApplicationIdPMetadataDataSource(t *testing.T) {
+	srv, client := testAccServer(t)
+
+	app, err := client.CreateApplicationWithContext(context.Background(), map[string]interface{}{"name": "portal", "realm_id": srv.DefaultRealmID()})
+	if err != nil {
+		t.Fatalf("unable to create application: %s", err)
+	}
+	published, _ := parseCertificatePEM(srv.SigningCert())
+	der := testCertificate(t, "explicit", time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))
+	explicit := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	config := func(settings string) string {
+		return srv.ProviderConfig() + fmt.Sprintf(`
+data "fortitokencloud_application_idp_metadata" "test" {
+  application_id = %q
+  %s
+}
+`, app.ID, settings)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// the certificate FTC publishes at the entity ID
+			{
+				Config: config(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "entity_id", app.EntityID),
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "sso_url", app.SsoUrl),
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "slo_url", app.SloUrl),
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "signing_cert", srv.SigningCert()),
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "fingerprint_sha1", fingerprintSHA1(published)),
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "fingerprint_sha256", fingerprintSHA256(published)),
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "signing_cert_expiry", formatExpiry(published)),
+					resource.TestMatchResourceAttr("data.fortitokencloud_application_idp_metadata.test", "metadata_xml", regexp.MustCompile(regexp.QuoteMeta(`entityID="`+app.EntityID+`"`))),
+					resource.TestMatchResourceAttr("data.fortitokencloud_application_idp_metadata.test", "metadata_xml", regexp.MustCompile(regexp.QuoteMeta(`Location="`+app.SsoUrl+`"`))),
+				),
+			},
+			{
+				Config: config(fmt.Sprintf("signing_cert = %q", explicit)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "signing_cert", explicit),
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "signing_cert_expiry", "2030-01-02T03:04:05Z"),
+					resource.TestMatchResourceAttr("data.fortitokencloud_application_idp_metadata.test", "metadata_xml", regexp.MustCompile(regexp.QuoteMeta(base64.StdEncoding.EncodeToString(der)))),
+				),
+			},
+			{
+				Config:      config(`signing_cert = "not a certificate"`),
+				ExpectError: regexp.MustCompile(`Invalid signing certificate`),
+			},
+		},
+	})
+}
+
+func TestAccApplicationIdPMetadataDataSourceErrors(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+data "fortitokencloud_application_idp_metadata" "test" {
+  application_id = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Unable to read application.*status: 404`),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_application" "test" {
+  name     = "portal"
+  type     = "oidc"
+  realm_id = data.fortitokencloud_realm.test.id
+
+  oidc {
+    redirect_uris = ["https://portal.example.com/callback"]
+  }
+}
+
+data "fortitokencloud_application_idp_metadata" "test" {
+  application_id = fortitokencloud_application.test.id
+}
+`,
+				ExpectError: regexp.MustCompile(`Unsupported application type`),
+			},
+		},
+	})
+}
@@ -0,0 +1,169 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &brandingDataSource{}
+	_ datasource.DataSourceWithConfigure = &brandingDataSource{}
+)
+
+func NewBrandingDataSource() datasource.DataSource {
+	return &brandingDataSource{}
+}
+
+// brandingDataSource is the data source implementation.
+type brandingDataSource struct {
+	client *ftc_client.Client
+}
+
+// brandingDataSourceModel maps the data source schema data.
+type brandingDataSourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	RealmID         types.String `tfsdk:"realm_id"`
+	Title           types.String `tfsdk:"title"`
+	PrimaryColor    types.String `tfsdk:"primary_color"`
+	BackgroundColor types.String `tfsdk:"background_color"`
+	TextColor       types.String `tfsdk:"text_color"`
+	FooterText      types.String `tfsdk:"footer_text"`
+	LogoHash        types.String `tfsdk:"logo_hash"`
+}
+
+func (d *brandingDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_branding"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *brandingDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ftc_client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ftc_client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// Schema defines the schema for the data source.
+func (d *brandingDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up login page branding by name.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"realm_id": schema.StringAttribute{
+				Description: "Realm to search, required when several realms have branding with the same name.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"title": schema.StringAttribute{
+				Computed: true,
+			},
+			"primary_color": schema.StringAttribute{
+				Computed: true,
+			},
+			"background_color": schema.StringAttribute{
+				Computed: true,
+			},
+			"text_color": schema.StringAttribute{
+				Computed: true,
+			},
+			"footer_text": schema.StringAttribute{
+				Computed: true,
+			},
+			"logo_hash": schema.StringAttribute{
+				Description: "SHA-256 of the logo content, empty without a logo.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// findBrandings walks every page of brandings and keeps the ones with name, in realm_id when set.
+func findBrandings(ctx context.Context, client *ftc_client.Client, realm_id, name string) ([]ftc_client.Branding, error) {
+	var matches []ftc_client.Branding
+	pager := client.NewBrandingsPager(ftc_client.ListOptions{})
+	for pager.Next(ctx) {
+		for _, branding := range pager.Page() {
+			if realm_id != "" && branding.RealmID != realm_id {
+				continue
+			}
+			if branding.Name != name {
+				continue
+			}
+			matches = append(matches, branding)
+		}
+	}
+	return matches, pager.Err()
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *brandingDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config brandingDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	matches, err := findBrandings(ctx, d.client, config.RealmID.ValueString(), config.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read branding",
+			err.Error(),
+		)
+		return
+	}
+	if len(matches) != 1 {
+		detail := fmt.Sprintf("Expected a single branding named %q, got %d.", config.Name.ValueString(), len(matches))
+		if len(matches) > 1 && config.RealmID.IsNull() {
+			detail += " Set realm_id to pick one."
+		}
+		resp.Diagnostics.AddError(
+			"Unable to read branding",
+			detail,
+		)
+		return
+	}
+
+	branding := matches[0]
+	state := brandingDataSourceModel{
+		ID:              types.StringValue(branding.ID),
+		Name:            types.StringValue(branding.Name),
+		RealmID:         types.StringValue(branding.RealmID),
+		Title:           types.StringValue(branding.Title),
+		PrimaryColor:    types.StringValue(branding.PrimaryColor),
+		BackgroundColor: types.StringValue(branding.BackgroundColor),
+		TextColor:       types.StringValue(branding.TextColor),
+		FooterText:      types.StringValue(branding.FooterText),
+		LogoHash:        types.StringValue(brandingLogoHash(branding.Logo)),
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,71 @@
+package fortitokencloud
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBrandingDataSource(t *testing.T) {
+	srv, client := testAccServer(t)
+	ctx := context.Background()
+	_, logo := testLogo(t, 1)
+
+	other, err := client.CreatRealmWithContext(ctx, map[string]interface{}{"name": "other"})
+	if err != nil {
+		t.Fatalf("unable to create realm: %s", err)
+	}
+	for _, realm_id := range []string{srv.DefaultRealmID(), other.ID} {
+		_, err := client.CreateBrandingWithContext(ctx, map[string]interface{}{
+			"name":          "corporate",
+			"realm_id":      realm_id,
+			"title":         "Sign in",
+			"primary_color": "#1A2B3C",
+			"logo":          base64.StdEncoding.EncodeToString(logo),
+		})
+		if err != nil {
+			t.Fatalf("unable to create branding: %s", err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + fmt.Sprintf(`
+data "fortitokencloud_branding" "test" {
+  name     = "corporate"
+  realm_id = %q
+}
+`, other.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.fortitokencloud_branding.test", "id"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_branding.test", "realm_id", other.ID),
+					resource.TestCheckResourceAttr("data.fortitokencloud_branding.test", "title", "Sign in"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_branding.test", "primary_color", "#1A2B3C"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_branding.test", "logo_hash", hashLogo(logo)),
+				),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+data "fortitokencloud_branding" "test" {
+  name = "corporate"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)got 2.*Set realm_id to pick\s+one`),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+data "fortitokencloud_branding" "test" {
+  name = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`Expected a single branding named "missing",\s+got 0`),
+			},
+		},
+	})
+}
@@ -33,7 +33,7 @@ func (d *realmDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	realm, err := d.client.GetRealmByName(data.Name.ValueString())
+	realm, err := d.client.GetRealmByNameWithContext(ctx, data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to read realm",
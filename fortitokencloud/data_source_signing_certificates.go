@@ -0,0 +1,172 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &signingCertificatesDataSource{}
+	_ datasource.DataSourceWithConfigure = &signingCertificatesDataSource{}
+)
+
+func NewSigningCertificatesDataSource() datasource.DataSource {
+	return &signingCertificatesDataSource{}
+}
+
+// signingCertificatesDataSource is the data source implementation.
+type signingCertificatesDataSource struct {
+	client *ftc_client.Client
+}
+
+// signingCertificatesDataSourceModel maps the data source schema data.
+type signingCertificatesDataSourceModel struct {
+	RealmID      types.String              `tfsdk:"realm_id"`
+	Certificates []signingCertificateModel `tfsdk:"certificates"`
+}
+
+// signingCertificateModel maps signing certificate schema data.
+type signingCertificateModel struct {
+	ID                types.String `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	RealmID           types.String `tfsdk:"realm_id"`
+	CertificatePEM    types.String `tfsdk:"certificate_pem"`
+	Subject           types.String `tfsdk:"subject"`
+	Issuer            types.String `tfsdk:"issuer"`
+	SerialNumber      types.String `tfsdk:"serial_number"`
+	NotBefore         types.String `tfsdk:"not_before"`
+	NotAfter          types.String `tfsdk:"not_after"`
+	FingerprintSHA1   types.String `tfsdk:"fingerprint_sha1"`
+	FingerprintSHA256 types.String `tfsdk:"fingerprint_sha256"`
+}
+
+func (d *signingCertificatesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_signing_certificates"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *signingCertificatesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ftc_client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ftc_client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// Schema defines the schema for the data source.
+func (d *signingCertificatesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the IdP signing certificates of a realm.",
+		Attributes: map[string]schema.Attribute{
+			"realm_id": schema.StringAttribute{
+				Required: true,
+			},
+			"certificates": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed: true,
+						},
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"realm_id": schema.StringAttribute{
+							Computed: true,
+						},
+						"certificate_pem": schema.StringAttribute{
+							Computed: true,
+						},
+						"subject": schema.StringAttribute{
+							Computed: true,
+						},
+						"issuer": schema.StringAttribute{
+							Computed: true,
+						},
+						"serial_number": schema.StringAttribute{
+							Computed: true,
+						},
+						"not_before": schema.StringAttribute{
+							Computed: true,
+						},
+						"not_after": schema.StringAttribute{
+							Description: "Expiry of the certificate in RFC 3339 format.",
+							Computed:    true,
+						},
+						"fingerprint_sha1": schema.StringAttribute{
+							Computed: true,
+						},
+						"fingerprint_sha256": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *signingCertificatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state signingCertificatesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Walk every page, keeping the certificates of the realm
+	state.Certificates = []signingCertificateModel{}
+	pager := d.client.NewSigningCertificatesPager(ftc_client.ListOptions{})
+	for pager.Next(ctx) {
+		for _, cert := range pager.Page() {
+			if cert.RealmID != state.RealmID.ValueString() {
+				continue
+			}
+			attributes := certificateAttributes(cert.Certificate)
+			state.Certificates = append(state.Certificates, signingCertificateModel{
+				ID:                types.StringValue(cert.ID),
+				Name:              types.StringValue(cert.Name),
+				RealmID:           types.StringValue(cert.RealmID),
+				CertificatePEM:    types.StringValue(cert.Certificate),
+				Subject:           types.StringValue(attributes["subject"]),
+				Issuer:            types.StringValue(attributes["issuer"]),
+				SerialNumber:      types.StringValue(attributes["serial_number"]),
+				NotBefore:         types.StringValue(attributes["not_before"]),
+				NotAfter:          types.StringValue(attributes["not_after"]),
+				FingerprintSHA1:   types.StringValue(attributes["fingerprint_sha1"]),
+				FingerprintSHA256: types.StringValue(attributes["fingerprint_sha256"]),
+			})
+		}
+	}
+	if err := pager.Err(); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read FTC Signing Certificates",
+			err.Error(),
+		)
+		return
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
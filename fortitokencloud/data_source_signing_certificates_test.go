@@ -0,0 +1,73 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSigningCertificatesDataSource(t *testing.T) {
+	srv, client := testAccServer(t)
+	ctx := context.Background()
+	cert_pem, key_pem := testKeyPair(t, "idp.example.com", time.Now().Add(24*time.Hour))
+	attributes := certificateAttributes(cert_pem)
+
+	other, err := client.CreatRealmWithContext(ctx, map[string]interface{}{"name": "other"})
+	if err != nil {
+		t.Fatalf("unable to create realm: %s", err)
+	}
+	uploaded, err := client.CreateSigningCertificateWithContext(ctx, map[string]interface{}{
+		"name": "uploaded", "realm_id": srv.DefaultRealmID(), "certificate": cert_pem, "private_key": key_pem,
+	})
+	if err != nil {
+		t.Fatalf("unable to create signing certificate: %s", err)
+	}
+	if _, err := client.CreateSigningCertificateWithContext(ctx, map[string]interface{}{
+		"name": "generated", "realm_id": other.ID, "common_name": "other.example.com",
+	}); err != nil {
+		t.Fatalf("unable to create signing certificate: %s", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// only the certificates of the realm are listed
+			{
+				Config: srv.ProviderConfig() + fmt.Sprintf(`
+data "fortitokencloud_signing_certificates" "test" {
+  realm_id = %q
+}
+`, srv.DefaultRealmID()),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fortitokencloud_signing_certificates.test", "certificates.#", "1"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_signing_certificates.test", "certificates.0.id", uploaded.ID),
+					resource.TestCheckResourceAttr("data.fortitokencloud_signing_certificates.test", "certificates.0.name", "uploaded"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_signing_certificates.test", "certificates.0.subject", "CN=idp.example.com"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_signing_certificates.test", "certificates.0.fingerprint_sha256", attributes["fingerprint_sha256"]),
+					resource.TestCheckResourceAttr("data.fortitokencloud_signing_certificates.test", "certificates.0.not_after", attributes["not_after"]),
+				),
+			},
+			// an application's signing certificate is advertised in its IdP metadata
+			{
+				Config: srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_application" "test" {
+  name            = "portal"
+  realm_id        = %q
+  signing_cert_id = %q
+}
+
+data "fortitokencloud_application_idp_metadata" "test" {
+  application_id = fortitokencloud_application.test.id
+}
+`, srv.DefaultRealmID(), uploaded.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "signing_cert", uploaded.Certificate),
+					resource.TestCheckResourceAttr("data.fortitokencloud_application_idp_metadata.test", "fingerprint_sha256", attributes["fingerprint_sha256"]),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,489 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                   = &userSourcesDataSource{}
+	_ datasource.DataSourceWithConfigure      = &userSourcesDataSource{}
+	_ datasource.DataSource                   = &userSourceDataSource{}
+	_ datasource.DataSourceWithConfigure      = &userSourceDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &userSourceDataSource{}
+)
+
+func NewUserSourcesDataSource() datasource.DataSource {
+	return &userSourcesDataSource{}
+}
+
+func NewUserSourceDataSource() datasource.DataSource {
+	return &userSourceDataSource{}
+}
+
+// userSourcesDataSource is the list data source implementation.
+type userSourcesDataSource struct {
+	client *ftc_client.Client
+}
+
+// userSourceDataSource is the single object data source implementation.
+type userSourceDataSource struct {
+	client *ftc_client.Client
+}
+
+func (d *userSourcesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_usersources"
+}
+
+func (d *userSourceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_usersource"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *userSourcesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ftc_client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ftc_client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *userSourceDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ftc_client.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ftc_client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+// userSourceAttributes returns the computed attributes describing a user source.
+func userSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"realm_id": schema.StringAttribute{
+			Computed: true,
+		},
+		"type": schema.StringAttribute{
+			Computed: true,
+		},
+		"prefix": schema.StringAttribute{
+			Computed: true,
+		},
+		"entity_id": schema.StringAttribute{
+			Computed: true,
+		},
+		"login_url": schema.StringAttribute{
+			Computed: true,
+		},
+		"logout_url": schema.StringAttribute{
+			Computed: true,
+		},
+		"post_binding": schema.BoolAttribute{
+			Computed: true,
+		},
+		"include_subject": schema.BoolAttribute{
+			Computed: true,
+		},
+		"signing_cert": schema.StringAttribute{
+			Description: "PEM encoded IdP signing certificate of a SAML user source.",
+			Computed:    true,
+		},
+		"auth_uri": schema.StringAttribute{
+			Computed: true,
+		},
+		"token_uri": schema.StringAttribute{
+			Computed: true,
+		},
+		"userinfo_uri": schema.StringAttribute{
+			Computed: true,
+		},
+		"logout_uri": schema.StringAttribute{
+			Computed: true,
+		},
+		"issuer": schema.StringAttribute{
+			Computed: true,
+		},
+		"client_id": schema.StringAttribute{
+			Computed: true,
+		},
+		"username_assertion": schema.StringAttribute{
+			Computed: true,
+		},
+		"login_hint": schema.StringAttribute{
+			Computed: true,
+		},
+		"fqdn": schema.StringAttribute{
+			Computed: true,
+		},
+		"attr_mapping": schema.MapAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"proxy_prefix": schema.StringAttribute{
+			Computed: true,
+		},
+		"proxy_entity_id": schema.StringAttribute{
+			Computed: true,
+		},
+		"proxy_acs_url": schema.StringAttribute{
+			Computed: true,
+		},
+		"proxy_slo_url": schema.StringAttribute{
+			Computed: true,
+		},
+		"proxy_sso_url": schema.StringAttribute{
+			Computed: true,
+		},
+		"proxy_callback_url": schema.StringAttribute{
+			Computed: true,
+		},
+		"proxy_post_logout_redirect_uri": schema.StringAttribute{
+			Computed: true,
+		},
+		"proxy_oidc_login_url": schema.StringAttribute{
+			Computed: true,
+		},
+		"domains": schema.ListNestedAttribute{
+			Computed: true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed: true,
+					},
+					"name": schema.StringAttribute{
+						Computed: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Schema defines the schema for the data source.
+func (d *userSourcesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"realm_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return user sources of this realm.",
+			},
+			"type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return user sources of this type, saml or oidc.",
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return user sources with this name.",
+			},
+			"usersources": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: userSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// Schema defines the schema for the data source.
+func (d *userSourceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := userSourceAttributes()
+	attributes["id"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the user source. Either id, or realm_id and name, must be set.",
+	}
+	attributes["realm_id"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Realm to look the user source up in by name.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Name of the user source, unique within its realm.",
+	}
+	resp.Schema = schema.Schema{
+		Attributes: attributes,
+	}
+}
+
+// userSourcesDataSourceModel maps the list data source schema data.
+type userSourcesDataSourceModel struct {
+	RealmID     types.String      `tfsdk:"realm_id"`
+	Type        types.String      `tfsdk:"type"`
+	Name        types.String      `tfsdk:"name"`
+	UserSources []userSourceModel `tfsdk:"usersources"`
+}
+
+// userSourceModel maps user source schema data.
+type userSourceModel struct {
+	ID                         types.String         `tfsdk:"id"`
+	Name                       types.String         `tfsdk:"name"`
+	RealmID                    types.String         `tfsdk:"realm_id"`
+	Type                       types.String         `tfsdk:"type"`
+	Prefix                     types.String         `tfsdk:"prefix"`
+	EntityID                   types.String         `tfsdk:"entity_id"`
+	LoginUrl                   types.String         `tfsdk:"login_url"`
+	LogoutUrl                  types.String         `tfsdk:"logout_url"`
+	PostBinding                types.Bool           `tfsdk:"post_binding"`
+	IncludeSubject             types.Bool           `tfsdk:"include_subject"`
+	SigningCert                types.String         `tfsdk:"signing_cert"`
+	AuthUri                    types.String         `tfsdk:"auth_uri"`
+	TokenUri                   types.String         `tfsdk:"token_uri"`
+	UserInfoUri                types.String         `tfsdk:"userinfo_uri"`
+	LogoutUri                  types.String         `tfsdk:"logout_uri"`
+	Issuer                     types.String         `tfsdk:"issuer"`
+	ClientID                   types.String         `tfsdk:"client_id"`
+	UsernameAssertion          types.String         `tfsdk:"username_assertion"`
+	LoginHint                  types.String         `tfsdk:"login_hint"`
+	FQDN                       types.String         `tfsdk:"fqdn"`
+	AttrMapping                types.Map            `tfsdk:"attr_mapping"`
+	ProxyPrefix                types.String         `tfsdk:"proxy_prefix"`
+	ProxyEntityID              types.String         `tfsdk:"proxy_entity_id"`
+	ProxyAcsUrl                types.String         `tfsdk:"proxy_acs_url"`
+	ProxySloUrl                types.String         `tfsdk:"proxy_slo_url"`
+	ProxySSoUrl                types.String         `tfsdk:"proxy_sso_url"`
+	ProxyCallbackUrl           types.String         `tfsdk:"proxy_callback_url"`
+	ProxyPostLogoutRedirectUri types.String         `tfsdk:"proxy_post_logout_redirect_uri"`
+	ProxyOidcLoginUrl          types.String         `tfsdk:"proxy_oidc_login_url"`
+	Domains                    []domainElementModel `tfsdk:"domains"`
+}
+
+// domainElementModel maps a domain attached to a user source.
+type domainElementModel struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+}
+
+// newUserSourceModel maps an API user source to the data source model.
+func newUserSourceModel(usersource ftc_client.UserSource) userSourceModel {
+	domains := make([]domainElementModel, 0)
+	for _, domain := range usersource.Domains {
+		domains = append(domains, domainElementModel{
+			ID:   types.StringValue(domain.ID),
+			Name: types.StringValue(domain.Name),
+		})
+	}
+	return userSourceModel{
+		ID:                         types.StringValue(usersource.ID),
+		Name:                       types.StringValue(usersource.Name),
+		RealmID:                    types.StringValue(usersource.RealmID),
+		Type:                       types.StringValue(type_int_map[int64(usersource.Type)]),
+		Prefix:                     types.StringValue(usersource.Prefix),
+		EntityID:                   types.StringValue(usersource.EntityID),
+		LoginUrl:                   types.StringValue(usersource.LoginUrl),
+		LogoutUrl:                  types.StringValue(usersource.LogoutUrl),
+		PostBinding:                types.BoolValue(usersource.PostBinding),
+		IncludeSubject:             types.BoolValue(usersource.IncludeSubject),
+		SigningCert:                types.StringValue(usersource.SigningCert),
+		AuthUri:                    types.StringValue(usersource.AuthUri),
+		TokenUri:                   types.StringValue(usersource.TokenUri),
+		UserInfoUri:                types.StringValue(usersource.UserInfoUri),
+		LogoutUri:                  types.StringValue(usersource.LogoutUri),
+		Issuer:                     types.StringValue(usersource.Issuer),
+		ClientID:                   types.StringValue(usersource.ClientID),
+		UsernameAssertion:          types.StringValue(usersource.UsernameAssertion),
+		LoginHint:                  types.StringValue(usersource.LoginHint),
+		FQDN:                       types.StringValue(usersource.FQDN),
+		AttrMapping:                attrMappingValue(usersource.AttrMapping),
+		ProxyPrefix:                types.StringValue(usersource.ProxySP.Prefix),
+		ProxyEntityID:              types.StringValue(usersource.ProxySP.EntityID),
+		ProxyAcsUrl:                types.StringValue(usersource.ProxySP.AcsUrl),
+		ProxySloUrl:                types.StringValue(usersource.ProxySP.SloUrl),
+		ProxySSoUrl:                types.StringValue(usersource.ProxySP.SsoUrl),
+		ProxyCallbackUrl:           types.StringValue(usersource.ProxySP.CallbackUrl),
+		ProxyPostLogoutRedirectUri: types.StringValue(usersource.ProxySP.PostLogoutRedirectUrl),
+		ProxyOidcLoginUrl:          types.StringValue(usersource.ProxySP.OidcLoginUrl),
+		Domains:                    domains,
+	}
+}
+
+// findUserSources walks every page of user sources and keeps the ones matching all non-empty filters.
+func findUserSources(ctx context.Context, client *ftc_client.Client, realm_id, us_type, name string) ([]ftc_client.UserSource, error) {
+	var matches []ftc_client.UserSource
+	pager := client.NewUserSourcesPager(ftc_client.ListOptions{})
+	for pager.Next(ctx) {
+		for _, usersource := range pager.Page() {
+			if realm_id != "" && usersource.RealmID != realm_id {
+				continue
+			}
+			if us_type != "" && type_int_map[int64(usersource.Type)] != strings.ToLower(us_type) {
+				continue
+			}
+			if name != "" && usersource.Name != name {
+				continue
+			}
+			matches = append(matches, usersource)
+		}
+	}
+	return matches, pager.Err()
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *userSourcesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state userSourcesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if us_type := strings.ToLower(state.Type.ValueString()); us_type != "" {
+		if _, ok := type_str_map[us_type]; !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("type"),
+				"Invalid user source type",
+				fmt.Sprintf("Expected saml or oidc, got: %s.", state.Type.ValueString()),
+			)
+			return
+		}
+	}
+
+	usersources, err := findUserSources(ctx, d.client, state.RealmID.ValueString(), state.Type.ValueString(), state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read FTC User Sources",
+			err.Error(),
+		)
+		return
+	}
+
+	// Map response body to model
+	state.UserSources = make([]userSourceModel, 0)
+	for _, usersource := range usersources {
+		state.UserSources = append(state.UserSources, newUserSourceModel(usersource))
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// ValidateConfig requires either id, or realm_id together with name.
+func (d *userSourceDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config userSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Unknown values are only resolved during apply, check them then
+	if config.ID.IsUnknown() || config.RealmID.IsUnknown() || config.Name.IsUnknown() {
+		return
+	}
+
+	if !config.ID.IsNull() {
+		if !config.Name.IsNull() || !config.RealmID.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("id"),
+				"Conflicting user source lookup",
+				"Set either id, or realm_id and name, not both.",
+			)
+		}
+		return
+	}
+
+	if config.Name.IsNull() || config.RealmID.IsNull() {
+		resp.Diagnostics.AddError(
+			"Missing user source lookup",
+			"Set either id, or realm_id and name, to look up a user source.",
+		)
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *userSourceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config userSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var usersource *ftc_client.UserSource
+	if config.ID.ValueString() != "" {
+		found, err := d.client.GetUserSourceWithContext(ctx, config.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to read user source",
+				err.Error(),
+			)
+			return
+		}
+		usersource = found
+	} else {
+		matches, err := findUserSources(ctx, d.client, config.RealmID.ValueString(), "", config.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to read user source",
+				err.Error(),
+			)
+			return
+		}
+		if len(matches) != 1 {
+			resp.Diagnostics.AddError(
+				"Unable to read user source",
+				fmt.Sprintf("Expected a single user source named %q in realm %s, got %d.", config.Name.ValueString(), config.RealmID.ValueString(), len(matches)),
+			)
+			return
+		}
+		// the list endpoint may omit details such as domains, fetch the full object
+		found, err := d.client.GetUserSourceWithContext(ctx, matches[0].ID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to read user source",
+				err.Error(),
+			)
+			return
+		}
+		usersource = found
+	}
+
+	state := newUserSourceModel(*usersource)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
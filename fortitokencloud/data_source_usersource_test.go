@@ -0,0 +1,217 @@
+package fortitokencloud
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+	"terraform-provider-fortitokencloud/sdk/ftctest"
+)
+
+// testAccUserSourceFixtures creates a SAML and an OIDC user source in the default realm and
+// a SAML user source with the same name in a second realm, returning the IDs of the user
+// sources in the default realm and of the second realm.
+func testAccUserSourceFixtures(t *testing.T, srv *ftctest.Server, client *ftc_client.Client) (string, string, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	other, err := client.CreatRealmWithContext(ctx, map[string]interface{}{"name": "other"})
+	if err != nil {
+		t.Fatalf("unable to create realm: %s", err)
+	}
+	domain, err := client.CreateDomainWithContext(ctx, map[string]interface{}{"name": "example.com", "realm_id": srv.DefaultRealmID()})
+	if err != nil {
+		t.Fatalf("unable to create domain: %s", err)
+	}
+
+	signing_cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testCertificate(t, "azure", time.Now().AddDate(1, 0, 0))})
+	saml := map[string]interface{}{
+		"name": "azure",
+		"type": 1,
+		"saml_params": map[string]interface{}{
+			"entity_id":    "https://sts.windows.net/tenant/",
+			"login_url":    "https://login.example.com/saml2",
+			"signing_cert": string(signing_cert),
+		},
+		"attr_mapping": map[string]interface{}{"Username": "email", "groups": []interface{}{"admins"}},
+	}
+	saml["realm_id"] = srv.DefaultRealmID()
+	azure, err := client.CreateUserSourceWithContext(ctx, saml)
+	if err != nil {
+		t.Fatalf("unable to create user source: %s", err)
+	}
+	if _, err := client.UpdateUserSourceDomainsWithContext(ctx, azure.ID, map[string][]string{"domain_ids": {domain.ID}}); err != nil {
+		t.Fatalf("unable to attach domain: %s", err)
+	}
+	saml["realm_id"] = other.ID
+	if _, err := client.CreateUserSourceWithContext(ctx, saml); err != nil {
+		t.Fatalf("unable to create user source: %s", err)
+	}
+
+	okta, err := client.CreateUserSourceWithContext(ctx, map[string]interface{}{
+		"name":     "okta",
+		"type":     2,
+		"realm_id": srv.DefaultRealmID(),
+		"oidc_params": map[string]interface{}{
+			"auth_uri":  "https://okta.example.com/oauth2/v1/authorize",
+			"token_uri": "https://okta.example.com/oauth2/v1/token",
+			"client_id": "client",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create user source: %s", err)
+	}
+	return azure.ID, okta.ID, other.ID
+}
+
+func TestAccUserSourcesDataSource(t *testing.T) {
+	srv, client := testAccServer(t)
+	_, _, other_realm_id := testAccUserSourceFixtures(t, srv, client)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + fmt.Sprintf(`
+data "fortitokencloud_usersources" "all" {}
+
+data "fortitokencloud_usersources" "realm" {
+  realm_id = %q
+}
+
+data "fortitokencloud_usersources" "oidc" {
+  type = "OIDC"
+}
+
+data "fortitokencloud_usersources" "azure" {
+  name = "azure"
+}
+
+data "fortitokencloud_usersources" "other" {
+  realm_id = %q
+  type     = "saml"
+}
+`, srv.DefaultRealmID(), other_realm_id),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersources.all", "usersources.#", "3"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersources.realm", "usersources.#", "2"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersources.oidc", "usersources.#", "1"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersources.oidc", "usersources.0.type", "oidc"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersources.oidc", "usersources.0.client_id", "client"),
+					resource.TestCheckResourceAttrSet("data.fortitokencloud_usersources.oidc", "usersources.0.proxy_callback_url"),
+					resource.TestCheckResourceAttrSet("data.fortitokencloud_usersources.oidc", "usersources.0.fqdn"),
+					resource.TestCheckResourceAttrSet("data.fortitokencloud_usersources.oidc", "usersources.0.proxy_prefix"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersources.oidc", "usersources.0.signing_cert", ""),
+					resource.TestMatchResourceAttr("data.fortitokencloud_usersources.other", "usersources.0.signing_cert", regexp.MustCompile(`^-----BEGIN CERTIFICATE-----`)),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersources.azure", "usersources.#", "2"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersources.other", "usersources.#", "1"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersources.other", "usersources.0.realm_id", other_realm_id),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersources.other", "usersources.0.domains.#", "0"),
+				),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+data "fortitokencloud_usersources" "test" {
+  type = "ldap"
+}
+`,
+				ExpectError: regexp.MustCompile(`Expected saml or oidc, got: ldap`),
+			},
+		},
+	})
+}
+
+func TestAccUserSourceDataSource(t *testing.T) {
+	srv, client := testAccServer(t)
+	azure_id, okta_id, _ := testAccUserSourceFixtures(t, srv, client)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + fmt.Sprintf(`
+data "fortitokencloud_usersource" "by_name" {
+  realm_id = %q
+  name     = "azure"
+}
+
+data "fortitokencloud_usersource" "by_id" {
+  id = %q
+}
+`, srv.DefaultRealmID(), okta_id),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_name", "id", azure_id),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_name", "type", "saml"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_name", "login_url", "https://login.example.com/saml2"),
+					resource.TestCheckResourceAttrSet("data.fortitokencloud_usersource.by_name", "proxy_acs_url"),
+					resource.TestMatchResourceAttr("data.fortitokencloud_usersource.by_name", "signing_cert", regexp.MustCompile(`^-----BEGIN CERTIFICATE-----`)),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_name", "fqdn", srv.Listener.Addr().String()),
+					resource.TestCheckResourceAttrSet("data.fortitokencloud_usersource.by_name", "proxy_prefix"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_name", "attr_mapping.Username", "email"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_name", "attr_mapping.groups", `["admins"]`),
+					// the list endpoint is not trusted with the domains, the full object is read
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_name", "domains.#", "1"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_name", "domains.0.name", "example.com"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_id", "name", "okta"),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_id", "realm_id", srv.DefaultRealmID()),
+					resource.TestCheckResourceAttr("data.fortitokencloud_usersource.by_id", "auth_uri", "https://okta.example.com/oauth2/v1/authorize"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserSourceDataSourceErrors(t *testing.T) {
+	srv, client := testAccServer(t)
+	testAccUserSourceFixtures(t, srv, client)
+
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "id and name",
+			config: fmt.Sprintf(`id = "us"%s  realm_id = %q%s  name = "azure"`, "\n", srv.DefaultRealmID(), "\n"),
+			err:    `Set either id, or realm_id and name, not both`,
+		},
+		{
+			name:   "name without realm",
+			config: `name = "azure"`,
+			err:    `Set either id, or realm_id and name, to look up a user source`,
+		},
+		{
+			name:   "no match",
+			config: fmt.Sprintf(`realm_id = %q%s  name = "missing"`, srv.DefaultRealmID(), "\n"),
+			err:    `Expected a single user source named "missing"`,
+		},
+		{
+			name:   "missing id",
+			config: `id = "missing"`,
+			err:    `status: 404`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: srv.ProviderConfig() + `
+data "fortitokencloud_usersource" "test" {
+  ` + tt.config + `
+}
+`,
+						ExpectError: regexp.MustCompile(regexp.QuoteMeta(tt.err)),
+					},
+				},
+			})
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package fortitokencloud
+
+import (
+	"slices"
+	"sync"
+)
+
+// keyedMutex hands out one mutex per key, so read-modify-write cycles on the same FTC object
+// are serialised while changes to different objects run in parallel.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// Lock locks key and returns the function that unlocks it.
+func (m *keyedMutex) Lock(key string) func() {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = map[string]*sync.Mutex{}
+	}
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.locks[key] = lock
+	}
+	m.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+var (
+	// applicationLocks serialises changes to the user source list of an application.
+	applicationLocks keyedMutex
+	// userSourceLocks serialises changes to the domain list of a user source.
+	userSourceLocks keyedMutex
+)
+
+// sameIDs reports whether a and b hold the same IDs, ignoring order.
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := map[string]bool{}
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// changeIDs applies the difference between planned and wanted to current: IDs only in planned
+// are dropped, IDs only in wanted are added and everything else in current is kept.
+func changeIDs(current, planned, wanted []string) []string {
+	ids := make([]string, 0, len(current)+len(wanted))
+	for _, id := range current {
+		if slices.Contains(wanted, id) || !slices.Contains(planned, id) {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range wanted {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,103 @@
+package fortitokencloud
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	var locks keyedMutex
+
+	unlock := locks.Lock("app-1")
+
+	// another key is not blocked
+	done := make(chan struct{})
+	go func() {
+		locks.Lock("app-2")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("locking app-2 blocked while app-1 was locked")
+	}
+
+	// the same key waits for the unlock
+	acquired := make(chan struct{})
+	go func() {
+		locks.Lock("app-1")()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("app-1 was locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("app-1 was not released by unlock")
+	}
+}
+
+func TestKeyedMutexSerialises(t *testing.T) {
+	var locks keyedMutex
+	var wg sync.WaitGroup
+
+	// read-modify-write cycles on one key lose no updates
+	counter := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer locks.Lock("app-1")()
+			value := counter
+			time.Sleep(time.Microsecond)
+			counter = value + 1
+		}()
+	}
+	wg.Wait()
+	if counter != 50 {
+		t.Errorf("counter = %d after 50 locked increments", counter)
+	}
+}
+
+func TestSameIDs(t *testing.T) {
+	cases := []struct {
+		a, b []string
+		want bool
+	}{
+		{nil, []string{}, true},
+		{[]string{"a", "b"}, []string{"b", "a"}, true},
+		{[]string{"a", "b"}, []string{"a"}, false},
+		{[]string{"a", "b"}, []string{"a", "c"}, false},
+	}
+	for _, c := range cases {
+		if got := sameIDs(c.a, c.b); got != c.want {
+			t.Errorf("sameIDs(%v, %v) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestChangeIDs(t *testing.T) {
+	cases := []struct {
+		current, planned, wanted []string
+		want                     []string
+	}{
+		// nothing changed since the plan
+		{[]string{"a", "b"}, []string{"a", "b"}, []string{"b", "c"}, []string{"b", "c"}},
+		// c was attached since the plan and is kept
+		{[]string{"a", "c"}, []string{"a"}, []string{}, []string{"c"}},
+		// a was detached since the plan, removing it again is a no-op
+		{[]string{}, []string{"a", "b"}, []string{"b"}, []string{"b"}},
+		// a recreated application has nothing planned
+		{nil, nil, []string{"a"}, []string{"a"}},
+	}
+	for _, c := range cases {
+		if got := changeIDs(c.current, c.planned, c.wanted); !sameIDs(got, c.want) {
+			t.Errorf("changeIDs(%v, %v, %v) = %v, want %v", c.current, c.planned, c.wanted, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package fortitokencloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// oidcDiscoveryPath is appended to discovery URLs given as the bare issuer
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+// oidcDiscovery maps the endpoints of an OpenID Provider configuration document.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+// discoverOIDC reads the OpenID Provider configuration, client may be nil before the provider is configured.
+func discoverOIDC(ctx context.Context, client *http.Client, url string) (*oidcDiscovery, error) {
+	if !strings.HasSuffix(url, oidcDiscoveryPath) {
+		url = strings.TrimSuffix(url, "/") + oidcDiscoveryPath
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 399 {
+		return nil, fmt.Errorf("GET %s: status: %d", url, res.StatusCode)
+	}
+
+	discovery := oidcDiscovery{}
+	if err := json.NewDecoder(res.Body).Decode(&discovery); err != nil {
+		return nil, fmt.Errorf("GET %s: invalid discovery document: %w", url, err)
+	}
+	if discovery.Issuer == "" || discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" {
+		return nil, fmt.Errorf("GET %s: discovery document must contain issuer, authorization_endpoint and token_endpoint", url)
+	}
+
+	return &discovery, nil
+}
@@ -0,0 +1,82 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testDiscoveryServer serves an OpenID Provider configuration with issuer set to its own URL.
+func testDiscoveryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case oidcDiscoveryPath:
+			fmt.Fprintf(w, `{
+  "issuer": %[1]q,
+  "authorization_endpoint": "%[1]s/authorize",
+  "token_endpoint": "%[1]s/token",
+  "userinfo_endpoint": "%[1]s/userinfo",
+  "end_session_endpoint": "%[1]s/logout",
+  "jwks_uri": "%[1]s/keys"
+}`, srv.URL)
+		case "/incomplete" + oidcDiscoveryPath:
+			fmt.Fprintf(w, `{"issuer": %q}`, srv.URL)
+		case "/invalid" + oidcDiscoveryPath:
+			fmt.Fprint(w, "<html></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDiscoverOIDC(t *testing.T) {
+	srv := testDiscoveryServer(t)
+	want := oidcDiscovery{
+		Issuer:                srv.URL,
+		AuthorizationEndpoint: srv.URL + "/authorize",
+		TokenEndpoint:         srv.URL + "/token",
+		UserinfoEndpoint:      srv.URL + "/userinfo",
+		EndSessionEndpoint:    srv.URL + "/logout",
+	}
+
+	// the issuer, with or without the trailing slash, and the full document URL
+	for _, url := range []string{srv.URL, srv.URL + "/", srv.URL + oidcDiscoveryPath} {
+		discovery, err := discoverOIDC(context.Background(), nil, url)
+		if err != nil {
+			t.Fatalf("discoverOIDC(%s): %v", url, err)
+		}
+		if *discovery != want {
+			t.Errorf("discoverOIDC(%s) = %+v, want %+v", url, *discovery, want)
+		}
+	}
+}
+
+func TestDiscoverOIDCErrors(t *testing.T) {
+	srv := testDiscoveryServer(t)
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "missing document", url: srv.URL + "/missing", wantErr: "status: 404"},
+		{name: "not JSON", url: srv.URL + "/invalid", wantErr: "invalid discovery document"},
+		{name: "no endpoints", url: srv.URL + "/incomplete", wantErr: "must contain issuer, authorization_endpoint and token_endpoint"},
+		{name: "invalid URL", url: "http://[::1", wantErr: "missing ']'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := discoverOIDC(context.Background(), srv.Client(), tt.url)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got %v, want an error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
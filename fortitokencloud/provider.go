@@ -2,10 +2,15 @@ package fortitokencloud
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	ftc_client "terraform-provider-fortitokencloud/sdk"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -49,15 +54,68 @@ func (p *fortiTokenCloudProvider) Schema(_ context.Context, _ provider.SchemaReq
 				Optional:  true,
 				Sensitive: true,
 			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of retries for throttled (429) or unavailable (502, 503, 504) API responses. Defaults to 3, set to 0 to disable retries. Can also be set with FTC_MAX_RETRIES.",
+			},
+			"max_retry_wait": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of seconds to wait between two retries, including waits requested through Retry-After. Defaults to 30. Can also be set with FTC_MAX_RETRY_WAIT.",
+			},
+			"ca_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to, or PEM content of, a CA bundle trusted in addition to the system roots, e.g. for a TLS-intercepting proxy. Can also be set with FTC_CA_CERT.",
+			},
+			"client_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to, or PEM content of, a client certificate for mutual TLS. Requires client_key. Can also be set with FTC_CLIENT_CERT.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Path to, or PEM content of, the private key of client_cert. Can also be set with FTC_CLIENT_KEY.",
+			},
+			"insecure": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Skip verification of the FTC server certificate. Only use this in lab setups. Can also be set with FTC_INSECURE.",
+			},
+			"proxy": schema.StringAttribute{
+				Optional:    true,
+				Description: "URL of the HTTP(S) proxy used to reach FTC. Defaults to the HTTP_PROXY/HTTPS_PROXY environment variables. Can also be set with FTC_PROXY.",
+			},
+			"timeout": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Timeout of a single API request in seconds. Defaults to 10. Can also be set with FTC_TIMEOUT.",
+			},
+			"cert_expiry_warning_days": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Plans warn about SAML and signing certificates expiring within this many days, and fail on expired ones. Defaults to 30, set to 0 to only fail on expired certificates. Can also be set with FTC_CERT_EXPIRY_WARNING_DAYS.",
+			},
 		},
 	}
 }
 
 // fortiTokenCloudProviderModel maps provider schema data to a Go type.
 type fortiTokenCloudProviderModel struct {
-	Host         types.String `tfsdk:"host"`
-	ClientId     types.String `tfsdk:"clientid"`
-	ClientSecret types.String `tfsdk:"clientsecret"`
+	Host                  types.String `tfsdk:"host"`
+	ClientId              types.String `tfsdk:"clientid"`
+	ClientSecret          types.String `tfsdk:"clientsecret"`
+	MaxRetries            types.Int64  `tfsdk:"max_retries"`
+	MaxRetryWait          types.Int64  `tfsdk:"max_retry_wait"`
+	CACert                types.String `tfsdk:"ca_cert"`
+	ClientCert            types.String `tfsdk:"client_cert"`
+	ClientKey             types.String `tfsdk:"client_key"`
+	Insecure              types.Bool   `tfsdk:"insecure"`
+	Proxy                 types.String `tfsdk:"proxy"`
+	Timeout               types.Int64  `tfsdk:"timeout"`
+	CertExpiryWarningDays types.Int64  `tfsdk:"cert_expiry_warning_days"`
+}
+
+// resourceData is handed to resources by Configure, data sources only get the client.
+type resourceData struct {
+	client *ftc_client.Client
+	// certExpiryWarningDays is how many days ahead of expiry plans warn about a certificate
+	certExpiryWarningDays int64
 }
 
 // Metadata returns the provider type name.
@@ -128,6 +186,67 @@ func (p *fortiTokenCloudProvider) Configure(ctx context.Context, req provider.Co
 		clientsecret = config.ClientSecret.ValueString()
 	}
 
+	max_retries := int64(ftc_client.DefaultMaxRetries)
+	max_retry_wait := int64(ftc_client.DefaultMaxRetryWait / time.Second)
+
+	cert_expiry_warning_days := int64(defaultCertExpiryWarningDays)
+
+	if !config.MaxRetries.IsNull() && !config.MaxRetries.IsUnknown() {
+		max_retries = config.MaxRetries.ValueInt64()
+	} else {
+		envInt64(path.Root("max_retries"), "FTC_MAX_RETRIES", &max_retries, &resp.Diagnostics)
+	}
+
+	if !config.MaxRetryWait.IsNull() && !config.MaxRetryWait.IsUnknown() {
+		max_retry_wait = config.MaxRetryWait.ValueInt64()
+	} else {
+		envInt64(path.Root("max_retry_wait"), "FTC_MAX_RETRY_WAIT", &max_retry_wait, &resp.Diagnostics)
+	}
+
+	if !config.CertExpiryWarningDays.IsNull() && !config.CertExpiryWarningDays.IsUnknown() {
+		cert_expiry_warning_days = config.CertExpiryWarningDays.ValueInt64()
+	} else {
+		envInt64(path.Root("cert_expiry_warning_days"), "FTC_CERT_EXPIRY_WARNING_DAYS", &cert_expiry_warning_days, &resp.Diagnostics)
+	}
+
+	transport := ftc_client.TransportConfig{
+		CACert:     os.Getenv("FTC_CA_CERT"),
+		ClientCert: os.Getenv("FTC_CLIENT_CERT"),
+		ClientKey:  os.Getenv("FTC_CLIENT_KEY"),
+		Proxy:      os.Getenv("FTC_PROXY"),
+		Timeout:    ftc_client.DefaultTimeout,
+	}
+
+	if !config.CACert.IsNull() {
+		transport.CACert = config.CACert.ValueString()
+	}
+
+	if !config.ClientCert.IsNull() {
+		transport.ClientCert = config.ClientCert.ValueString()
+	}
+
+	if !config.ClientKey.IsNull() {
+		transport.ClientKey = config.ClientKey.ValueString()
+	}
+
+	if !config.Insecure.IsNull() && !config.Insecure.IsUnknown() {
+		transport.Insecure = config.Insecure.ValueBool()
+	} else {
+		envBool(path.Root("insecure"), "FTC_INSECURE", &transport.Insecure, &resp.Diagnostics)
+	}
+
+	if !config.Proxy.IsNull() {
+		transport.Proxy = config.Proxy.ValueString()
+	}
+
+	if !config.Timeout.IsNull() && !config.Timeout.IsUnknown() {
+		transport.Timeout = time.Duration(config.Timeout.ValueInt64()) * time.Second
+	} else {
+		timeout := int64(transport.Timeout / time.Second)
+		envInt64(path.Root("timeout"), "FTC_TIMEOUT", &timeout, &resp.Diagnostics)
+		transport.Timeout = time.Duration(timeout) * time.Second
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
@@ -161,12 +280,74 @@ func (p *fortiTokenCloudProvider) Configure(ctx context.Context, req provider.Co
 		)
 	}
 
+	if max_retries < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Invalid FortiTokenCloud API Max Retries",
+			"The max_retries value must be zero or greater.",
+		)
+	}
+
+	if max_retry_wait < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retry_wait"),
+			"Invalid FortiTokenCloud API Max Retry Wait",
+			"The max_retry_wait value must be zero or greater.",
+		)
+	}
+
+	if cert_expiry_warning_days < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("cert_expiry_warning_days"),
+			"Invalid Certificate Expiry Warning Days",
+			"The cert_expiry_warning_days value must be zero or greater.",
+		)
+	}
+
+	if transport.Timeout <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid FortiTokenCloud API Timeout",
+			"The timeout value must be greater than zero.",
+		)
+	}
+
+	if (transport.ClientCert == "") != (transport.ClientKey == "") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_cert"),
+			"Incomplete FortiTokenCloud API Client Certificate",
+			"Mutual TLS needs both client_cert and client_key (or FTC_CLIENT_CERT and FTC_CLIENT_KEY) to be set.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if transport.Insecure {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("insecure"),
+			"FortiTokenCloud API Certificate Verification Disabled",
+			"The provider does not verify the certificate of the FortiTokenCloud API host. "+
+				"Only use insecure in lab setups, configure ca_cert to trust a private or intercepting CA instead.",
+		)
+	}
+
+	httpClient, err := ftc_client.NewHTTPClient(transport)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Configure FortiTokenCloud API Transport",
+			"An error occurred when loading the TLS or proxy settings of the FortiTokenCloud API client.\n\n"+
+				"FortiTokenCloud Client Error: "+err.Error(),
+		)
+		return
+	}
+
 	// Create a new FortiTokenCloud client using the configuration values
-	client, err := ftc_client.NewClient(&host, &clientid, &clientsecret)
+	client, err := ftc_client.NewClientWithContext(ctx, &host, &clientid, &clientsecret,
+		ftc_client.WithHTTPClient(httpClient),
+		ftc_client.WithRetry(int(max_retries), time.Duration(max_retry_wait)*time.Second),
+	)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create FortiTokenCloud API Client",
@@ -180,20 +361,71 @@ func (p *fortiTokenCloudProvider) Configure(ctx context.Context, req provider.Co
 	// Make the FortiTokenCloud client available during DataSource and Resource
 	// type Configure methods.
 	resp.DataSourceData = client
-	resp.ResourceData = client
+	resp.ResourceData = &resourceData{
+		client:                client,
+		certExpiryWarningDays: cert_expiry_warning_days,
+	}
+}
+
+// envInt64 sets value from the integer environment variable name when it is set, reporting a
+// value that is not an integer on attribute, which the variable stands in for.
+func envInt64(attribute path.Path, name string, value *int64, diags *diag.Diagnostics) {
+	env, ok := os.LookupEnv(name)
+	if !ok || env == "" {
+		return
+	}
+	v, err := strconv.ParseInt(strings.TrimSpace(env), 10, 64)
+	if err != nil {
+		diags.AddAttributeError(
+			attribute,
+			"Invalid "+name+" Environment Variable",
+			fmt.Sprintf("%s must be a whole number, got %q.", name, env),
+		)
+		return
+	}
+	*value = v
+}
+
+// envBool sets value from the boolean environment variable name when it is set, reporting a
+// value that is not a boolean on attribute, which the variable stands in for.
+func envBool(attribute path.Path, name string, value *bool, diags *diag.Diagnostics) {
+	env, ok := os.LookupEnv(name)
+	if !ok || env == "" {
+		return
+	}
+	v, err := strconv.ParseBool(strings.TrimSpace(env))
+	if err != nil {
+		diags.AddAttributeError(
+			attribute,
+			"Invalid "+name+" Environment Variable",
+			fmt.Sprintf("%s must be true or false, got %q.", name, env),
+		)
+		return
+	}
+	*value = v
 }
 
 func (p *fortiTokenCloudProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewApplicationsDataSource,
+		NewApplicationIdPMetadataDataSource,
 		NewRealmDataSource,
+		NewBrandingDataSource,
+		NewSigningCertificatesDataSource,
+		NewUserSourcesDataSource,
+		NewUserSourceDataSource,
 	}
 }
 
 func (p *fortiTokenCloudProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewApplicationResource,
+		NewApplicationUserSourceAttachmentResource,
 		NewUserSourceResource,
+		NewUserSourceDomainAttachmentResource,
 		NewDomainResource,
+		NewRealmResource,
+		NewBrandingResource,
+		NewSigningCertificateResource,
 	}
 }
@@ -0,0 +1,158 @@
+package fortitokencloud
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+	"terraform-provider-fortitokencloud/sdk/ftctest"
+)
+
+// testAccProtoV6ProviderFactories are used to instantiate the provider during acceptance testing.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"fortitokencloud": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccServer starts an in-memory FTC API for one test and returns it with an SDK client
+// used to inspect or change it behind Terraform's back.
+func testAccServer(t *testing.T) (*ftctest.Server, *ftc_client.Client) {
+	t.Helper()
+	srv := ftctest.NewServer(t)
+	t.Cleanup(srv.Close)
+
+	host, clientID, clientSecret := srv.URL, ftctest.ClientID, ftctest.ClientSecret
+	client, err := ftc_client.NewClient(&host, &clientID, &clientSecret)
+	if err != nil {
+		t.Fatalf("unable to sign in to the test server: %s", err)
+	}
+	return srv, client
+}
+
+func TestAccProviderConfigure(t *testing.T) {
+	srv, _ := testAccServer(t)
+	t.Setenv("FTC_HOST", "")
+	t.Setenv("FTC_CLIENTID", "")
+	t.Setenv("FTC_CLIENTSECRET", "")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "fortitokencloud" {
+  clientid     = "id"
+  clientsecret = "secret"
+}
+
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+`,
+				ExpectError: regexp.MustCompile(`Missing FortiTokenCloud API Host`),
+			},
+			{
+				Config: `
+provider "fortitokencloud" {
+  host         = "` + srv.URL + `"
+  clientid     = "` + ftctest.ClientID + `"
+  clientsecret = "` + ftctest.ClientSecret + `"
+  max_retries  = -1
+}
+
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+`,
+				ExpectError: regexp.MustCompile(`max_retries value must be zero or greater`),
+			},
+			{
+				Config: `
+provider "fortitokencloud" {
+  host                     = "` + srv.URL + `"
+  clientid                 = "` + ftctest.ClientID + `"
+  clientsecret             = "` + ftctest.ClientSecret + `"
+  cert_expiry_warning_days = -1
+}
+
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+`,
+				ExpectError: regexp.MustCompile(`cert_expiry_warning_days value must be zero or\s+greater`),
+			},
+			{
+				Config: `
+provider "fortitokencloud" {
+  host         = "` + srv.URL + `"
+  clientid     = "` + ftctest.ClientID + `"
+  clientsecret = "wrong"
+  max_retries  = 0
+}
+
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+`,
+				ExpectError: regexp.MustCompile(`status: 401`),
+			},
+		},
+	})
+}
+
+func TestAccProviderConfigureEnvironment(t *testing.T) {
+	srv, _ := testAccServer(t)
+	config := func(settings string) string {
+		return `
+provider "fortitokencloud" {
+  host         = "` + srv.URL + `"
+  clientid     = "` + ftctest.ClientID + `"
+  clientsecret = "` + ftctest.ClientSecret + `"
+  ` + settings + `
+}
+
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+`
+	}
+
+	for name, want := range map[string]string{
+		"FTC_MAX_RETRIES":              "must be a whole number",
+		"FTC_MAX_RETRY_WAIT":           "must be a whole number",
+		"FTC_TIMEOUT":                  "must be a whole number",
+		"FTC_CERT_EXPIRY_WARNING_DAYS": "must be a whole number",
+		"FTC_INSECURE":                 "must be true or false",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, "ten")
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config:      config(""),
+						ExpectError: regexp.MustCompile(`(?s)Invalid ` + name + ` Environment Variable.*` + name + ` ` + want + `, got\s+"ten"`),
+					},
+				},
+			})
+		})
+	}
+
+	// the configuration takes precedence, an unused variable is not checked
+	t.Run("configured", func(t *testing.T) {
+		t.Setenv("FTC_MAX_RETRIES", "ten")
+		t.Setenv("FTC_TIMEOUT", "5")
+		resource.Test(t, resource.TestCase{
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: config("max_retries = 0"),
+					Check:  resource.TestCheckResourceAttrSet("data.fortitokencloud_realm.test", "id"),
+				},
+			},
+		})
+	})
+}
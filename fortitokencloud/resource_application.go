@@ -2,27 +2,60 @@ package fortitokencloud
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net/url"
 	"reflect"
+	"slices"
 	"strings"
 	ftc_client "terraform-provider-fortitokencloud/sdk"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &applicationResource{}
-	_ resource.ResourceWithConfigure   = &applicationResource{}
-	_ resource.ResourceWithImportState = &applicationResource{}
+	_ resource.Resource                   = &applicationResource{}
+	_ resource.ResourceWithConfigure      = &applicationResource{}
+	_ resource.ResourceWithImportState    = &applicationResource{}
+	_ resource.ResourceWithValidateConfig = &applicationResource{}
+	_ resource.ResourceWithModifyPlan     = &applicationResource{}
+	_ resource.ResourceWithUpgradeState   = &applicationResource{}
+)
+
+var (
+	// application type codes, the ones fortitokencloud_applications reports as type
+	app_type_int_map = map[int64]string{
+		1: "saml",
+		2: "oidc",
+	}
+	app_type_str_map = map[string]int64{
+		"saml": 1,
+		"oidc": 2,
+	}
+	// grant types an OIDC application can allow
+	app_oidc_grant_types = []string{"authorization_code", "refresh_token", "client_credentials"}
+	// token lifetimes of an OIDC application and their defaults in seconds
+	app_oidc_lifetimes = map[string]int64{
+		"access_token_lifetime":  3600,
+		"id_token_lifetime":      3600,
+		"refresh_token_lifetime": 86400,
+	}
+)
+
+// bounds of the OIDC token lifetimes in seconds
+const (
+	minOidcTokenLifetime = 60
+	maxOidcTokenLifetime = 2592000
 )
 
 // NewApplicationResource is a helper function to simplify the provider implementation.
@@ -32,31 +65,49 @@ func NewApplicationResource() resource.Resource {
 
 // applicationResource is the resource implementation.
 type applicationResource struct {
-	client *ftc_client.Client
+	client                *ftc_client.Client
+	certExpiryWarningDays int64
 }
 
 func formatAppObj(plan applicationResourceModel, create bool) (*map[string]interface{}, map[string][]string) {
 	obj := make(map[string]interface{})
-	user_source_list := make(map[string][]string)
 	obj["name"] = plan.Name.ValueString()
 	obj["ttl"] = plan.TTL.ValueInt64()
 	if create {
 		obj["realm_id"] = plan.RealmID.ValueString()
+		// SAML is what FTC creates without a type
+		if plan.Type.ValueString() != "saml" {
+			obj["type"] = app_type_str_map[plan.Type.ValueString()]
+		}
 	}
 	if plan.BrandingID.ValueString() == "" {
 		obj["branding_id"] = nil
 	} else {
 		obj["branding_id"] = plan.BrandingID.ValueString()
 	}
-	if plan.AttrMapping.ValueString() == "" {
+	if len(plan.AttrMapping.Elements()) == 0 {
 		obj["attr_mapping"] = nil
 	} else {
-		var attr_mapping map[string]interface{}
-		err := json.Unmarshal([]byte(plan.AttrMapping.ValueString()), &attr_mapping)
-		if err != nil {
-			return nil, user_source_list
+		obj["attr_mapping"] = attrMappingPayload(plan.AttrMapping)
+	}
+	if plan.Oidc != nil {
+		oidc_obj := map[string]interface{}{}
+		for key, values := range map[string][]types.String{
+			"redirect_uris": plan.Oidc.RedirectURIs,
+			"scopes":        plan.Oidc.Scopes,
+			"grant_types":   plan.Oidc.GrantTypes,
+		} {
+			list := make([]string, 0, len(values))
+			for _, value := range values {
+				list = append(list, value.ValueString())
+			}
+			oidc_obj[key] = list
 		}
-		obj["attr_mapping"] = attr_mapping
+		oidc_obj["access_token_lifetime"] = plan.Oidc.AccessTokenLifetime.ValueInt64()
+		oidc_obj["id_token_lifetime"] = plan.Oidc.IDTokenLifetime.ValueInt64()
+		oidc_obj["refresh_token_lifetime"] = plan.Oidc.RefreshTokenLifetime.ValueInt64()
+		obj["oidc_params"] = oidc_obj
+		return &obj, formatAppUserSources(plan)
 	}
 	saml_obj := map[string]interface{}{}
 	if plan.SigningCertID.ValueString() == "" {
@@ -79,14 +130,29 @@ func formatAppObj(plan applicationResourceModel, create bool) (*map[string]inter
 	} else {
 		saml_obj["slo_url"] = plan.SPSloURL.ValueString()
 	}
+	if plan.SPNameID.ValueString() == "" {
+		saml_obj["name_id"] = nil
+	} else {
+		saml_obj["name_id"] = plan.SPNameID.ValueString()
+	}
+	if plan.SPSigningCert.ValueString() == "" {
+		saml_obj["signing_cert"] = nil
+	} else {
+		saml_obj["signing_cert"] = plan.SPSigningCert.ValueString()
+	}
 	obj["saml_params"] = saml_obj
+	return &obj, formatAppUserSources(plan)
+}
 
+// formatAppUserSources builds the user source mapping payload of an application.
+func formatAppUserSources(plan applicationResourceModel) map[string][]string {
+	user_source_list := make(map[string][]string)
 	var user_source_ids []string
 	for _, user_source_id := range plan.UserSources {
 		user_source_ids = append(user_source_ids, user_source_id.ValueString())
 	}
 	user_source_list["user_source_ids"] = user_source_ids
-	return &obj, user_source_list
+	return user_source_list
 }
 
 // Metadata returns the resource type name.
@@ -100,23 +166,25 @@ func (r *applicationResource) Configure(_ context.Context, req resource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*ftc_client.Client)
+	data, ok := req.ProviderData.(*resourceData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *ftc_client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.certExpiryWarningDays = data.certExpiryWarningDays
 }
 
 // Schema defines the schema for the resource.
 func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed: true,
@@ -127,6 +195,15 @@ func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest
 			"name": schema.StringAttribute{
 				Required: true,
 			},
+			"type": schema.StringAttribute{
+				Description: "Application type, \"saml\" (default) or \"oidc\". OIDC applications are configured in the oidc block. Changing it creates a new application.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("saml"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(replaceIfSet(), "Changing the type creates a new application.", "Changing the type creates a new application."),
+				},
+			},
 			"entity_id": schema.StringAttribute{
 				Computed: true,
 			},
@@ -143,18 +220,20 @@ func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Computed: true,
 			},
 			"branding_id": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
+				Description: "ID of a fortitokencloud_branding in the same realm.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
 			},
 			"ttl": schema.Int64Attribute{
 				Optional: true,
 				Computed: true,
 			},
 			"signing_cert_id": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
+				Description: "ID of a fortitokencloud_signing_certificate in the same realm used to sign assertions, the realm default when empty.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
 			},
 			"sp_entity_id": schema.StringAttribute{
 				Optional: true,
@@ -162,19 +241,117 @@ func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest
 			},
 			"sp_acs_url": schema.StringAttribute{
 				Optional: true,
+				Computed: true,
 			},
 			"sp_slo_url": schema.StringAttribute{
 				Optional: true,
+				Computed: true,
+			},
+			"sp_name_id": schema.StringAttribute{
+				Description: "NameID format URN requested for the SP, e.g. urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"sp_signing_cert": schema.StringAttribute{
+				Description: "PEM encoded certificate the SP signs AuthnRequests and logout messages with.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"sp_metadata_xml": schema.StringAttribute{
+				Description: "SAML SP metadata document used to fill sp_entity_id, sp_acs_url, sp_slo_url, sp_name_id and sp_signing_cert.",
+				Optional:    true,
+			},
+			"sp_metadata_url": schema.StringAttribute{
+				Description: "URL of the SAML SP metadata document, fetched at plan time. Conflicts with sp_metadata_xml.",
+				Optional:    true,
 			},
 			"user_source_ids": schema.SetAttribute{
+				Description: "IDs of the user sources attached to the application. Leave unset to manage them with fortitokencloud_application_usersource_attachment instead.",
 				Optional:    true,
 				ElementType: types.StringType,
 				Computed:    true,
-				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
 			},
-			"attr_mapping": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
+			"attr_mapping": schema.MapAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"oidc": schema.SingleNestedBlock{
+				Description: "OIDC relying party settings, required when type = \"oidc\".",
+				Attributes: map[string]schema.Attribute{
+					"redirect_uris": schema.SetAttribute{
+						Description: "Redirect URIs the client may use, https or http://localhost. Required for the authorization_code grant.",
+						Optional:    true,
+						Computed:    true,
+						ElementType: types.StringType,
+						Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+					},
+					"scopes": schema.SetAttribute{
+						Description: "Scopes the client may request, must include openid.",
+						Optional:    true,
+						Computed:    true,
+						ElementType: types.StringType,
+						Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{types.StringValue("openid")})),
+					},
+					"grant_types": schema.SetAttribute{
+						Description: "Grant types the client may use, any of authorization_code (default), refresh_token and client_credentials.",
+						Optional:    true,
+						Computed:    true,
+						ElementType: types.StringType,
+						Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{types.StringValue("authorization_code")})),
+					},
+					"access_token_lifetime": schema.Int64Attribute{
+						Description: "Access token lifetime in seconds, 3600 when not set.",
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(app_oidc_lifetimes["access_token_lifetime"]),
+					},
+					"id_token_lifetime": schema.Int64Attribute{
+						Description: "ID token lifetime in seconds, 3600 when not set.",
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(app_oidc_lifetimes["id_token_lifetime"]),
+					},
+					"refresh_token_lifetime": schema.Int64Attribute{
+						Description: "Refresh token lifetime in seconds, 86400 when not set.",
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(app_oidc_lifetimes["refresh_token_lifetime"]),
+					},
+					"client_id": schema.StringAttribute{
+						Description: "Client ID FTC generates for the application.",
+						Computed:    true,
+						Sensitive:   true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"client_secret": schema.StringAttribute{
+						Description: "Client secret FTC generates for the application. Only returned when the application is created, empty after import.",
+						Computed:    true,
+						Sensitive:   true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"issuer": schema.StringAttribute{
+						Description: "Issuer of the tokens FTC issues for the application.",
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"discovery_url": schema.StringAttribute{
+						Description: "OpenID Provider configuration URL of the application.",
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+				},
 			},
 		},
 	}
@@ -182,21 +359,358 @@ func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest
 
 // applicationResourceModel maps the resource schema data.
 type applicationResourceModel struct {
-	ID            types.String   `tfsdk:"id"`
-	Name          types.String   `tfsdk:"name"`
-	EntityID      types.String   `tfsdk:"entity_id"`
-	SsoUrl        types.String   `tfsdk:"sso_url"`
-	SloUrl        types.String   `tfsdk:"slo_url"`
-	RealmID       types.String   `tfsdk:"realm_id"`
-	Prefix        types.String   `tfsdk:"prefix"`
-	BrandingID    types.String   `tfsdk:"branding_id"`
-	TTL           types.Int64    `tfsdk:"ttl"`
-	SigningCertID types.String   `tfsdk:"signing_cert_id"`
-	SPEntityID    types.String   `tfsdk:"sp_entity_id"`
-	SPAcsURL      types.String   `tfsdk:"sp_acs_url"`
-	SPSloURL      types.String   `tfsdk:"sp_slo_url"`
-	UserSources   []types.String `tfsdk:"user_source_ids"`
-	AttrMapping   types.String   `tfsdk:"attr_mapping"`
+	ID            types.String          `tfsdk:"id"`
+	Name          types.String          `tfsdk:"name"`
+	Type          types.String          `tfsdk:"type"`
+	EntityID      types.String          `tfsdk:"entity_id"`
+	SsoUrl        types.String          `tfsdk:"sso_url"`
+	SloUrl        types.String          `tfsdk:"slo_url"`
+	RealmID       types.String          `tfsdk:"realm_id"`
+	Prefix        types.String          `tfsdk:"prefix"`
+	BrandingID    types.String          `tfsdk:"branding_id"`
+	TTL           types.Int64           `tfsdk:"ttl"`
+	SigningCertID types.String          `tfsdk:"signing_cert_id"`
+	SPEntityID    types.String          `tfsdk:"sp_entity_id"`
+	SPAcsURL      types.String          `tfsdk:"sp_acs_url"`
+	SPSloURL      types.String          `tfsdk:"sp_slo_url"`
+	SPNameID      types.String          `tfsdk:"sp_name_id"`
+	SPSigningCert types.String          `tfsdk:"sp_signing_cert"`
+	SPMetadataXML types.String          `tfsdk:"sp_metadata_xml"`
+	SPMetadataURL types.String          `tfsdk:"sp_metadata_url"`
+	UserSources   []types.String        `tfsdk:"user_source_ids"`
+	AttrMapping   types.Map             `tfsdk:"attr_mapping"`
+	Oidc          *applicationOidcModel `tfsdk:"oidc"`
+}
+
+// applicationType returns the type name of an application, SAML for codes it does not know.
+func applicationType(application *ftc_client.Application) string {
+	if app_type, ok := app_type_int_map[int64(application.Type)]; ok {
+		return app_type
+	}
+	return "saml"
+}
+
+// applicationOidcModel maps the oidc block.
+type applicationOidcModel struct {
+	RedirectURIs         []types.String `tfsdk:"redirect_uris"`
+	Scopes               []types.String `tfsdk:"scopes"`
+	GrantTypes           []types.String `tfsdk:"grant_types"`
+	AccessTokenLifetime  types.Int64    `tfsdk:"access_token_lifetime"`
+	IDTokenLifetime      types.Int64    `tfsdk:"id_token_lifetime"`
+	RefreshTokenLifetime types.Int64    `tfsdk:"refresh_token_lifetime"`
+	ClientID             types.String   `tfsdk:"client_id"`
+	ClientSecret         types.String   `tfsdk:"client_secret"`
+	Issuer               types.String   `tfsdk:"issuer"`
+	DiscoveryURL         types.String   `tfsdk:"discovery_url"`
+}
+
+// applicationOidcModelFrom builds the oidc block of an application returned by FTC, nil for
+// SAML applications. FTC only returns the client secret on create, so it is carried over from prior.
+func applicationOidcModelFrom(application *ftc_client.Application, prior *applicationOidcModel) *applicationOidcModel {
+	if application.OidcParams == nil {
+		return nil
+	}
+	params := application.OidcParams
+	stringValues := func(values []string) []types.String {
+		list := make([]types.String, 0, len(values))
+		for _, value := range values {
+			list = append(list, types.StringValue(value))
+		}
+		return list
+	}
+	model := &applicationOidcModel{
+		RedirectURIs:         stringValues(params.RedirectURIs),
+		Scopes:               stringValues(params.Scopes),
+		GrantTypes:           stringValues(params.GrantTypes),
+		AccessTokenLifetime:  types.Int64Value(int64(params.AccessTokenLifetime)),
+		IDTokenLifetime:      types.Int64Value(int64(params.IDTokenLifetime)),
+		RefreshTokenLifetime: types.Int64Value(int64(params.RefreshTokenLifetime)),
+		ClientID:             types.StringValue(params.ClientID),
+		ClientSecret:         types.StringValue(params.ClientSecret),
+		Issuer:               types.StringValue(params.Issuer),
+		DiscoveryURL:         types.StringValue(params.DiscoveryURL),
+	}
+	if params.ClientSecret == "" && prior != nil && !prior.ClientSecret.IsUnknown() && !prior.ClientSecret.IsNull() {
+		model.ClientSecret = prior.ClientSecret
+	}
+	return model
+}
+
+// UpgradeState migrates state written before attr_mapping became a map.
+func (r *applicationResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var current resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &current)
+	return map[int64]resource.StateUpgrader{
+		0: upgradeAttrMapping(current.Schema),
+	}
+}
+
+// ValidateConfig rejects setting both SP metadata sources, checks the type against the SAML
+// attributes and the oidc block, and checks the OIDC settings.
+func (r *applicationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var metadata_xml, metadata_url, app_type types.String
+	var oidc types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sp_metadata_xml"), &metadata_xml)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sp_metadata_url"), &metadata_url)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &app_type)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("oidc"), &oidc)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !metadata_xml.IsNull() && !metadata_url.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("sp_metadata_url"),
+			"Conflicting SP metadata",
+			"Only one of sp_metadata_xml and sp_metadata_url can be set.",
+		)
+	}
+
+	if !oidc.IsNull() && !oidc.IsUnknown() {
+		validateAppOidc(oidc.Attributes(), &resp.Diagnostics)
+	}
+
+	if app_type.IsUnknown() {
+		return
+	}
+	if _, ok := app_type_str_map[app_type.ValueString()]; !ok && !app_type.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid application type",
+			fmt.Sprintf("type must be \"saml\" or \"oidc\", got %q.", app_type.ValueString()),
+		)
+		return
+	}
+
+	if app_type.ValueString() != "oidc" {
+		if !oidc.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("oidc"),
+				"Settings block not supported by application type",
+				"The oidc block only applies to OIDC applications, set type = \"oidc\".",
+			)
+		}
+		return
+	}
+	if oidc.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("oidc"),
+			"Missing application settings",
+			"An oidc block is required when type = \"oidc\".",
+		)
+	}
+	// SAML settings do not apply to OIDC applications
+	for _, attribute := range []string{"signing_cert_id", "sp_entity_id", "sp_acs_url", "sp_slo_url", "sp_name_id", "sp_signing_cert",
+		"sp_metadata_xml", "sp_metadata_url"} {
+		var value types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(attribute), &value)...)
+		if isConfigured(value) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Attribute not supported by application type",
+				attribute+" only applies to SAML applications, but type is \"oidc\".",
+			)
+		}
+	}
+}
+
+// validateAppOidc checks the scopes, grant types, redirect URIs and token lifetimes configured
+// in the oidc block, skipping values that are not known yet.
+func validateAppOidc(values map[string]attr.Value, diags *diag.Diagnostics) {
+	block := path.Root("oidc")
+	elements := func(attribute string) ([]string, bool) {
+		set, ok := values[attribute].(types.Set)
+		if !ok || set.IsNull() || set.IsUnknown() {
+			return nil, ok && set.IsNull()
+		}
+		list := []string{}
+		for _, element := range set.Elements() {
+			value, ok := element.(types.String)
+			if !ok || value.IsUnknown() {
+				return nil, false
+			}
+			list = append(list, value.ValueString())
+		}
+		return list, true
+	}
+
+	if scopes, known := elements("scopes"); known && scopes != nil && !slices.Contains(scopes, "openid") {
+		diags.AddAttributeError(
+			block.AtName("scopes"),
+			"Invalid OIDC scopes",
+			"scopes must include openid.",
+		)
+	}
+
+	grant_types, grants_known := elements("grant_types")
+	if grants_known && grant_types == nil {
+		grant_types = []string{"authorization_code"}
+	}
+	for _, grant_type := range grant_types {
+		if !slices.Contains(app_oidc_grant_types, grant_type) {
+			diags.AddAttributeError(
+				block.AtName("grant_types"),
+				"Invalid OIDC grant type",
+				fmt.Sprintf("grant_types must only contain %s, got %q.", strings.Join(app_oidc_grant_types, ", "), grant_type),
+			)
+		}
+	}
+
+	redirect_uris, uris_known := elements("redirect_uris")
+	if grants_known && uris_known && len(redirect_uris) == 0 && slices.Contains(grant_types, "authorization_code") {
+		diags.AddAttributeError(
+			block.AtName("redirect_uris"),
+			"Missing redirect URIs",
+			"redirect_uris is required for the authorization_code grant.",
+		)
+	}
+	for _, redirect_uri := range redirect_uris {
+		if err := validateRedirectURI(redirect_uri); err != nil {
+			diags.AddAttributeError(
+				block.AtName("redirect_uris"),
+				"Invalid redirect URI",
+				fmt.Sprintf("Redirect URI %q is invalid: %s", redirect_uri, err),
+			)
+		}
+	}
+
+	for _, attribute := range []string{"access_token_lifetime", "id_token_lifetime", "refresh_token_lifetime"} {
+		lifetime, ok := values[attribute].(types.Int64)
+		if !ok || lifetime.IsNull() || lifetime.IsUnknown() {
+			continue
+		}
+		if lifetime.ValueInt64() < minOidcTokenLifetime || lifetime.ValueInt64() > maxOidcTokenLifetime {
+			diags.AddAttributeError(
+				block.AtName(attribute),
+				"Invalid token lifetime",
+				fmt.Sprintf("%s must be between %d and %d seconds, got %d.", attribute, minOidcTokenLifetime, maxOidcTokenLifetime, lifetime.ValueInt64()),
+			)
+		}
+	}
+}
+
+// validateRedirectURI checks that value is an https URL, or an http URL on localhost for
+// native and development clients, without a fragment.
+func validateRedirectURI(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host")
+	}
+	if u.Scheme != "https" && (u.Scheme != "http" || u.Hostname() != "localhost") {
+		return fmt.Errorf("must use https, or http on localhost")
+	}
+	if u.Fragment != "" {
+		return fmt.Errorf("must not contain a fragment")
+	}
+	return nil
+}
+
+// ModifyPlan fills the SP settings from sp_metadata_xml or sp_metadata_url, keeps the
+// attached user sources when user_source_ids is left to attachment resources, and checks the
+// resulting SP settings.
+func (r *applicationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to fill on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var user_source_ids types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("user_source_ids"), &user_source_ids)...)
+	if user_source_ids.IsNull() {
+		user_source_ids = types.SetValueMust(types.StringType, []attr.Value{})
+		if !req.State.Raw.IsNull() {
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("user_source_ids"), &user_source_ids)...)
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("user_source_ids"), user_source_ids)...)
+	}
+
+	var metadata_xml, metadata_url types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sp_metadata_xml"), &metadata_xml)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sp_metadata_url"), &metadata_url)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// SP settings the metadata can fill
+	fields := []string{"sp_entity_id", "sp_acs_url", "sp_slo_url", "sp_name_id", "sp_signing_cert"}
+
+	// metadata that is only known at apply time leaves the filled attributes unknown
+	if metadata_xml.IsUnknown() || metadata_url.IsUnknown() {
+		resp.Diagnostics.Append(planUnknown(ctx, req.Config, &resp.Plan, path.Empty(), fields)...)
+		return
+	}
+
+	source := path.Root("sp_metadata_xml")
+	if !metadata_url.IsNull() {
+		source = path.Root("sp_metadata_url")
+	}
+	data, err := loadMetadata(ctx, r.client, metadata_xml, metadata_url)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(source, "Error fetching SP metadata", err.Error())
+		return
+	}
+
+	values := map[string]string{}
+	if len(data) > 0 {
+		metadata, err := parseSPMetadata(data)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				source,
+				"Invalid SP metadata",
+				"Could not parse SAML SP metadata: "+err.Error(),
+			)
+			return
+		}
+		values["sp_entity_id"] = metadata.EntityID
+		values["sp_acs_url"] = metadata.AcsURL
+		values["sp_slo_url"] = metadata.SloURL
+		values["sp_name_id"] = metadata.NameIDFormat
+		values["sp_signing_cert"] = metadata.SigningCert
+	}
+
+	// sp_entity_id is left to FTC when neither the config nor the metadata sets it
+	defaults := map[string]string{"sp_acs_url": "", "sp_slo_url": "", "sp_name_id": "", "sp_signing_cert": ""}
+	resp.Diagnostics.Append(planFromMetadata(ctx, req.Config, &resp.Plan, path.Empty(), fields, values, defaults)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.planSPSettings(ctx, resp)
+}
+
+// planSPSettings checks the planned NameID format and SP signing certificate, whether set in
+// the configuration or taken from the metadata.
+func (r *applicationResource) planSPSettings(ctx context.Context, resp *resource.ModifyPlanResponse) {
+	var name_id, signing_cert types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("sp_name_id"), &name_id)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("sp_signing_cert"), &signing_cert)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !name_id.IsUnknown() && name_id.ValueString() != "" && !slices.Contains(samlNameIDFormats, name_id.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("sp_name_id"),
+			"Invalid NameID format",
+			fmt.Sprintf("sp_name_id must be one of %s, got %q.", strings.Join(samlNameIDFormats, ", "), name_id.ValueString()),
+		)
+	}
+
+	if signing_cert.IsUnknown() || signing_cert.ValueString() == "" {
+		return
+	}
+	if _, err := parseCertificatePEM(signing_cert.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("sp_signing_cert"),
+			"Invalid SP certificate",
+			"Could not parse sp_signing_cert as a PEM encoded X.509 certificate: "+err.Error(),
+		)
+		return
+	}
+	resp.Diagnostics.Append(checkCertificateExpiry(signing_cert.ValueString(), path.Root("sp_signing_cert"), r.certExpiryWarningDays, true)...)
 }
 
 // Create a new resource.
@@ -212,7 +726,7 @@ func (r *applicationResource) Create(ctx context.Context, req resource.CreateReq
 	obj, user_source_ids := formatAppObj(plan, true)
 
 	// Create new application
-	application, err := r.client.CreateApplication(obj)
+	application, err := r.client.CreateApplicationWithContext(ctx, obj)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating application",
@@ -221,11 +735,13 @@ func (r *applicationResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	resp.Diagnostics.Append(recordAttrMapping(ctx, resp.Private, application.AttrMapping)...)
+
 	// Map response body to schema and populate Computed attribute values
-	attr_mapping, _ := json.Marshal(application.AttrMapping)
 	plan = applicationResourceModel{
 		ID:            types.StringValue(application.ID),
 		Name:          types.StringValue(application.Name),
+		Type:          types.StringValue(applicationType(application)),
 		EntityID:      types.StringValue(application.EntityID),
 		SloUrl:        types.StringValue(application.SloUrl),
 		SsoUrl:        types.StringValue(application.SsoUrl),
@@ -237,15 +753,22 @@ func (r *applicationResource) Create(ctx context.Context, req resource.CreateReq
 		SPEntityID:    types.StringValue(application.SpEntityID),
 		SPAcsURL:      types.StringValue(application.SpAcsUrl),
 		SPSloURL:      types.StringValue(application.SpSloUrl),
-		AttrMapping:   types.StringValue(string(attr_mapping)),
+		SPNameID:      types.StringValue(application.SpNameID),
+		SPSigningCert: types.StringValue(application.SpSigningCert),
+		SPMetadataXML: plan.SPMetadataXML,
+		SPMetadataURL: plan.SPMetadataURL,
+		AttrMapping:   attrMappingValue(application.AttrMapping),
 		UserSources:   make([]types.String, 0),
+		Oidc:          applicationOidcModelFrom(application, plan.Oidc),
 	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
 
 	if len(user_source_ids["user_source_ids"]) > 0 {
-		_, err = r.client.UpdateApplicationUserSource(application.ID, user_source_ids)
+		unlock := applicationLocks.Lock(application.ID)
+		_, err = r.client.UpdateApplicationUserSourceWithContext(ctx, application.ID, user_source_ids)
+		unlock()
 
 		if err != nil {
 			resp.Diagnostics.AddError(
@@ -276,46 +799,47 @@ func (r *applicationResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	// if resource has been deleted upstream, reset state
-	if state.ID.ValueString() == "" {
-		state = applicationResourceModel{}
-	} else {
-		application, err := r.client.GetApplication(state.ID.ValueString())
-		if err != nil {
-			if !strings.Contains(err.Error(), "status: 404") {
-				resp.Diagnostics.AddError(
-					"Error Reading application",
-					"Could not read application ID "+state.ID.ValueString()+": "+err.Error(),
-				)
-				return
-			}
-			state = applicationResourceModel{}
-		} else {
-			new_user_sources := make([]types.String, 0)
-			// Overwrite items with refreshed state
-			attr_mapping, _ := json.Marshal(application.AttrMapping)
-			for _, usersource := range application.UserSources {
-				new_user_sources = append(new_user_sources, types.StringValue(usersource.ID))
-			}
-			state = applicationResourceModel{
-				ID:            types.StringValue(application.ID),
-				Name:          types.StringValue(application.Name),
-				EntityID:      types.StringValue(application.EntityID),
-				SloUrl:        types.StringValue(application.SloUrl),
-				SsoUrl:        types.StringValue(application.SsoUrl),
-				RealmID:       types.StringValue(application.RealmID),
-				Prefix:        types.StringValue(application.Prefix),
-				BrandingID:    types.StringValue(application.BrandingID),
-				TTL:           types.Int64Value(int64(application.TTL)),
-				SigningCertID: types.StringValue(application.SigningCertID),
-				SPEntityID:    types.StringValue(application.SpEntityID),
-				SPAcsURL:      types.StringValue(application.SpAcsUrl),
-				SPSloURL:      types.StringValue(application.SpSloUrl),
-				AttrMapping:   types.StringValue(string(attr_mapping)),
-				UserSources:   new_user_sources,
-			}
+	application, err := r.client.GetApplicationWithContext(ctx, state.ID.ValueString())
+	if ftc_client.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading application",
+			"Could not read application ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
 
-		}
+	resp.Diagnostics.Append(recordAttrMapping(ctx, resp.Private, application.AttrMapping)...)
+	new_user_sources := make([]types.String, 0)
+	// Overwrite items with refreshed state
+	for _, usersource := range application.UserSources {
+		new_user_sources = append(new_user_sources, types.StringValue(usersource.ID))
+	}
+	state = applicationResourceModel{
+		ID:            types.StringValue(application.ID),
+		Name:          types.StringValue(application.Name),
+		Type:          types.StringValue(applicationType(application)),
+		EntityID:      types.StringValue(application.EntityID),
+		SloUrl:        types.StringValue(application.SloUrl),
+		SsoUrl:        types.StringValue(application.SsoUrl),
+		RealmID:       types.StringValue(application.RealmID),
+		Prefix:        types.StringValue(application.Prefix),
+		BrandingID:    types.StringValue(application.BrandingID),
+		TTL:           types.Int64Value(int64(application.TTL)),
+		SigningCertID: types.StringValue(application.SigningCertID),
+		SPEntityID:    types.StringValue(application.SpEntityID),
+		SPAcsURL:      types.StringValue(application.SpAcsUrl),
+		SPSloURL:      types.StringValue(application.SpSloUrl),
+		SPNameID:      types.StringValue(application.SpNameID),
+		SPSigningCert: types.StringValue(application.SpSigningCert),
+		SPMetadataXML: state.SPMetadataXML,
+		SPMetadataURL: state.SPMetadataURL,
+		AttrMapping:   attrMappingValue(application.AttrMapping),
+		UserSources:   new_user_sources,
+		Oidc:          applicationOidcModelFrom(application, state.Oidc),
 	}
 
 	// Set refreshed state
@@ -343,13 +867,18 @@ func (r *applicationResource) Update(ctx context.Context, req resource.UpdateReq
 	new_user_sources := plan.UserSources
 
 	obj, user_source_list := formatAppObj(plan, false)
+	resp.Diagnostics.Append(restoreAttrMappingTypes(ctx, req.Private, obj)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	// if resource was deleted upstream, recreate it.
-	if plan.ID.ValueString() == "" {
+	recreated := plan.ID.ValueString() == ""
+	if recreated {
 		obj, user_source_list = formatAppObj(plan, true)
-		application, err = r.client.CreateApplication(obj)
+		application, err = r.client.CreateApplicationWithContext(ctx, obj)
 	} else {
 		// Update existing application
-		application, err = r.client.UpdateApplication(plan.ID.ValueString(), obj)
+		application, err = r.client.UpdateApplicationWithContext(ctx, plan.ID.ValueString(), obj)
 	}
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -359,10 +888,12 @@ func (r *applicationResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	attr_mapping, _ := json.Marshal(application.AttrMapping)
+	resp.Diagnostics.Append(recordAttrMapping(ctx, resp.Private, application.AttrMapping)...)
+
 	plan = applicationResourceModel{
 		ID:            types.StringValue(application.ID),
 		Name:          types.StringValue(application.Name),
+		Type:          types.StringValue(applicationType(application)),
 		EntityID:      types.StringValue(application.EntityID),
 		SloUrl:        types.StringValue(application.SloUrl),
 		SsoUrl:        types.StringValue(application.SsoUrl),
@@ -374,12 +905,27 @@ func (r *applicationResource) Update(ctx context.Context, req resource.UpdateReq
 		SPEntityID:    types.StringValue(application.SpEntityID),
 		SPAcsURL:      types.StringValue(application.SpAcsUrl),
 		SPSloURL:      types.StringValue(application.SpSloUrl),
-		AttrMapping:   types.StringValue(string(attr_mapping)),
+		SPNameID:      types.StringValue(application.SpNameID),
+		SPSigningCert: types.StringValue(application.SpSigningCert),
+		SPMetadataXML: plan.SPMetadataXML,
+		SPMetadataURL: plan.SPMetadataURL,
+		AttrMapping:   attrMappingValue(application.AttrMapping),
 		UserSources:   old_user_sources,
+		Oidc:          applicationOidcModelFrom(application, plan.Oidc),
 	}
 
 	if !reflect.DeepEqual(new_user_sources, old_user_sources) {
-		_, err = r.client.UpdateApplicationUserSource(plan.ID.ValueString(), user_source_list)
+		// only apply what the plan changed, so user sources attached or detached since the
+		// refresh, by attachment resources or another run, are not undone
+		var planned []string
+		if !recreated {
+			for _, user_source_id := range old_user_sources {
+				planned = append(planned, user_source_id.ValueString())
+			}
+		}
+		err = applicationUserSources.update(ctx, r.client, plan.ID.ValueString(), func(current []string) []string {
+			return changeIDs(current, planned, user_source_list["user_source_ids"])
+		})
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Updating application",
@@ -406,10 +952,10 @@ func (r *applicationResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	// Delete existing application
+	// Delete existing application, a 404 means it is already gone
 	if state.ID.ValueString() != "" {
-		err := r.client.DeleteApplication(state.ID.ValueString())
-		if err != nil {
+		err := r.client.DeleteApplicationWithContext(ctx, state.ID.ValueString())
+		if err != nil && !ftc_client.IsNotFound(err) {
 			resp.Diagnostics.AddError(
 				"Error Deleting Application",
 				"Could not delete application, unexpected error: "+err.Error(),
@@ -0,0 +1,565 @@
+package fortitokencloud
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"terraform-provider-fortitokencloud/sdk/ftctest"
+)
+
+func testAccApplicationConfig(name string, ttl int) string {
+	return fmt.Sprintf(`
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_application" "test" {
+  name         = %q
+  realm_id     = data.fortitokencloud_realm.test.id
+  sp_entity_id = "https://sp.example.com/saml/metadata"
+  sp_acs_url   = "https://sp.example.com/saml/login"
+  sp_slo_url   = "https://sp.example.com/saml/logout"
+  ttl          = %d
+  attr_mapping = {
+    username = "Username"
+    groups   = "[\"admins\",\"users\"]"
+  }
+}
+`, name, ttl)
+}
+
+// testAccApplicationID returns the ID of fortitokencloud_application.test in state.
+func testAccApplicationID(s *terraform.State) (string, error) {
+	rs, ok := s.RootModule().Resources["fortitokencloud_application.test"]
+	if !ok {
+		return "", fmt.Errorf("fortitokencloud_application.test not found in state")
+	}
+	return rs.Primary.ID, nil
+}
+
+// testAccCheckApplicationOnServer runs check against the application in state as stored by the server.
+func testAccCheckApplicationOnServer(srv *ftctest.Server, check func(app map[string]interface{}) error) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		id, err := testAccApplicationID(s)
+		if err != nil {
+			return err
+		}
+		app, ok := srv.Application(id)
+		if !ok {
+			return fmt.Errorf("application %s does not exist", id)
+		}
+		return check(map[string]interface{}{
+			"name":         app.Name,
+			"ttl":          app.TTL,
+			"attr_mapping": app.AttrMapping,
+		})
+	}
+}
+
+func testAccCheckApplicationDestroy(srv *ftctest.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "fortitokencloud_application" {
+				continue
+			}
+			if _, ok := srv.Application(rs.Primary.ID); ok {
+				return fmt.Errorf("application %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccApplicationResource(t *testing.T) {
+	srv, client := testAccServer(t)
+	var app_id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckApplicationDestroy(srv),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: srv.ProviderConfig() + testAccApplicationConfig("fgt_sslvpn", 900),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "name", "fgt_sslvpn"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "ttl", "900"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "sp_acs_url", "https://sp.example.com/saml/login"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "attr_mapping.%", "2"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "attr_mapping.username", "Username"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "attr_mapping.groups", `["admins","users"]`),
+					resource.TestCheckResourceAttrSet("fortitokencloud_application.test", "entity_id"),
+					resource.TestCheckResourceAttrSet("fortitokencloud_application.test", "sso_url"),
+					testAccCheckApplicationOnServer(srv, func(app map[string]interface{}) error {
+						if app["ttl"] != 900 {
+							return fmt.Errorf("ttl on server is %v, want 900", app["ttl"])
+						}
+						// values are sent as the strings configured
+						if !reflect.DeepEqual(app["attr_mapping"], map[string]interface{}{"username": "Username", "groups": `["admins","users"]`}) {
+							return fmt.Errorf("attr_mapping on server is %#v", app["attr_mapping"])
+						}
+						return nil
+					}),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "fortitokencloud_application.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: srv.ProviderConfig() + testAccApplicationConfig("fgt_sslvpn", 1800),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "ttl", "1800"),
+					testAccCheckApplicationOnServer(srv, func(app map[string]interface{}) error {
+						if app["ttl"] != 1800 {
+							return fmt.Errorf("ttl on server is %v, want 1800", app["ttl"])
+						}
+						return nil
+					}),
+					func(s *terraform.State) (err error) {
+						app_id, err = testAccApplicationID(s)
+						return err
+					},
+				),
+			},
+			// a list set outside Terraform matches the configured string and keeps its type on update
+			{
+				PreConfig: func() {
+					attr_mapping := map[string]interface{}{"username": "Username", "groups": []string{"admins", "users"}}
+					if _, err := client.UpdateApplicationWithContext(context.Background(), app_id, map[string]interface{}{"attr_mapping": attr_mapping}); err != nil {
+						t.Fatalf("unable to update application: %s", err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccApplicationConfig("fgt_sslvpn", 900),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "attr_mapping.groups", `["admins","users"]`),
+					testAccCheckApplicationOnServer(srv, func(app map[string]interface{}) error {
+						if app["ttl"] != 900 {
+							return fmt.Errorf("ttl on server is %v, want 900", app["ttl"])
+						}
+						if !reflect.DeepEqual(app["attr_mapping"], map[string]interface{}{"username": "Username", "groups": []interface{}{"admins", "users"}}) {
+							return fmt.Errorf("attr_mapping on server is %#v", app["attr_mapping"])
+						}
+						return nil
+					}),
+				),
+			},
+			// an application deleted outside Terraform is created again
+			{
+				PreConfig: func() {
+					if err := client.DeleteApplicationWithContext(context.Background(), app_id); err != nil {
+						t.Fatalf("deleting application: %s", err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccApplicationConfig("fgt_sslvpn", 900),
+				Check: func(s *terraform.State) error {
+					id, err := testAccApplicationID(s)
+					if err != nil {
+						return err
+					}
+					if id == app_id {
+						return fmt.Errorf("application %s was not created again", id)
+					}
+					if _, ok := srv.Application(id); !ok {
+						return fmt.Errorf("application %s does not exist", id)
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:  "fortitokencloud_application.test",
+				ImportState:   true,
+				ImportStateId: "missing",
+				ExpectError:   regexp.MustCompile(`Cannot import non-existent remote object`),
+			},
+		},
+	})
+}
+
+func TestAccApplicationResourceAPIErrors(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckApplicationDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "fortitokencloud_application" "test" {
+  name     = "app"
+  realm_id = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Could not create application.*realm missing does not exist`),
+			},
+			{
+				Config: srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_application" "test" {
+  name     = "app"
+  realm_id = %q
+}
+`, srv.DefaultRealmID()),
+				PreConfig:   func() { srv.FailNext(1, http.StatusServiceUnavailable, "") },
+				ExpectError: regexp.MustCompile(`(?s)Could not create application.*status: 503`),
+			},
+		},
+	})
+}
+
+func TestAccApplicationResourceSPMetadata(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	der := testCertificate(t, "sp.example.com", time.Now().Add(24*time.Hour))
+	metadata := testSPMetadata("https://sp.example.com/saml/metadata", der, nil)
+	metadataSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Replace(metadata, "/saml/acs", "/saml/acs/v2", 1))
+	}))
+	defer metadataSrv.Close()
+
+	config := func(source string) string {
+		return srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_application" "test" {
+  name     = "sp"
+  realm_id = %q
+  %s
+}
+`, srv.DefaultRealmID(), source)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckApplicationDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config(fmt.Sprintf("sp_metadata_xml = %q", metadata)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "sp_entity_id", "https://sp.example.com/saml/metadata"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "sp_acs_url", "https://sp.example.com/saml/acs"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "sp_slo_url", "https://sp.example.com/saml/slo"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "sp_name_id", "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "sp_signing_cert", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))),
+					func(s *terraform.State) error {
+						id, err := testAccApplicationID(s)
+						if err != nil {
+							return err
+						}
+						app, _ := srv.Application(id)
+						if app.SpAcsUrl != "https://sp.example.com/saml/acs" || app.SpSigningCert == "" {
+							return fmt.Errorf("application on server has ACS %q and signing certificate %q", app.SpAcsUrl, app.SpSigningCert)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: config(fmt.Sprintf("sp_metadata_url = %q", metadataSrv.URL)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "sp_acs_url", "https://sp.example.com/saml/acs/v2"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "sp_entity_id", "https://sp.example.com/saml/metadata"),
+				),
+			},
+			{
+				Config:      config(fmt.Sprintf("sp_metadata_url = %q\n  sp_acs_url = %q", metadataSrv.URL, "https://sp.example.com/other")),
+				ExpectError: regexp.MustCompile(`(?s)Metadata conflict.*sp_acs_url is set to`),
+			},
+			{
+				Config:      config(fmt.Sprintf("sp_metadata_url = %q\n  sp_metadata_xml = %q", metadataSrv.URL, metadata)),
+				ExpectError: regexp.MustCompile(`Only one of sp_metadata_xml and sp_metadata_url can be set`),
+			},
+			{
+				Config:      config(`sp_metadata_xml = "<EntityDescriptor/>"`),
+				ExpectError: regexp.MustCompile(`(?s)Invalid SP metadata.*does not contain an\s+SPSSODescriptor`),
+			},
+		},
+	})
+}
+
+func TestAccApplicationResourceCertificateExpiry(t *testing.T) {
+	srv, _ := testAccServer(t)
+	expired := testCertificate(t, "sp.example.com", time.Now().Add(-time.Hour))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_application" "test" {
+  name            = "app"
+  realm_id        = %q
+  sp_signing_cert = %q
+}
+`, srv.DefaultRealmID(), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: expired})),
+				ExpectError: regexp.MustCompile(`Certificate expired`),
+			},
+		},
+	})
+}
+
+func TestAccApplicationResourceSPSettings(t *testing.T) {
+	srv, _ := testAccServer(t)
+	signing := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testCertificate(t, "sp.example.com", time.Now().Add(90*24*time.Hour))}))
+
+	config := func(settings string) string {
+		return srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_application" "test" {
+  name         = "sp"
+  realm_id     = %q
+  sp_entity_id = "https://sp.example.com/saml/metadata"
+  %s
+}
+
+data "fortitokencloud_application_idp_metadata" "test" {
+  application_id = fortitokencloud_application.test.id
+}
+`, srv.DefaultRealmID(), settings)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckApplicationDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config(fmt.Sprintf(`sp_name_id      = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+  sp_signing_cert = %q`, signing)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "sp_name_id", "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "sp_signing_cert", signing),
+					resource.TestMatchResourceAttr("data.fortitokencloud_application_idp_metadata.test", "metadata_xml", regexp.MustCompile(regexp.QuoteMeta("<md:NameIDFormat>urn:oasis:names:tc:SAML:2.0:nameid-format:persistent</md:NameIDFormat>"))),
+					func(s *terraform.State) error {
+						id, err := testAccApplicationID(s)
+						if err != nil {
+							return err
+						}
+						app, _ := srv.Application(id)
+						if app.SpNameID != "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent" || app.SpSigningCert != signing {
+							return fmt.Errorf("application on server has NameID %q and signing certificate %q", app.SpNameID, app.SpSigningCert)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:      config(`sp_name_id = "email"`),
+				ExpectError: regexp.MustCompile(`(?s)Invalid NameID format.*got "email"`),
+			},
+			{
+				Config:      config(`sp_signing_cert = "not a certificate"`),
+				ExpectError: regexp.MustCompile(`Invalid SP certificate`),
+			},
+		},
+	})
+}
+
+func TestValidateRedirectURI(t *testing.T) {
+	for _, value := range []string{"https://app.example.com/callback", "http://localhost:8080/callback"} {
+		if err := validateRedirectURI(value); err != nil {
+			t.Errorf("validateRedirectURI(%q): %v", value, err)
+		}
+	}
+	for value, want := range map[string]string{
+		"/callback":                         "missing host",
+		"http://app.example.com/callback":   "must use https",
+		"ftp://app.example.com/callback":    "must use https",
+		"https://app.example.com/cb#tokens": "must not contain a fragment",
+	} {
+		if err := validateRedirectURI(value); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("validateRedirectURI(%q) = %v, want an error containing %q", value, err, want)
+		}
+	}
+}
+
+// testAccApplicationOidcConfig configures an OIDC application with the given access token lifetime.
+func testAccApplicationOidcConfig(access_token_lifetime int) string {
+	return fmt.Sprintf(`
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_application" "test" {
+  name     = "portal"
+  type     = "oidc"
+  realm_id = data.fortitokencloud_realm.test.id
+
+  oidc {
+    redirect_uris         = ["https://portal.example.com/callback"]
+    scopes                = ["openid", "email"]
+    grant_types           = ["authorization_code", "refresh_token"]
+    access_token_lifetime = %d
+  }
+}
+`, access_token_lifetime)
+}
+
+func TestAccApplicationResourceOidc(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckApplicationDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccApplicationOidcConfig(900),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "type", "oidc"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "oidc.scopes.#", "2"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "oidc.access_token_lifetime", "900"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "oidc.id_token_lifetime", "3600"),
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "oidc.refresh_token_lifetime", "86400"),
+					resource.TestCheckResourceAttrSet("fortitokencloud_application.test", "oidc.client_id"),
+					resource.TestCheckResourceAttrSet("fortitokencloud_application.test", "oidc.client_secret"),
+					resource.TestMatchResourceAttr("fortitokencloud_application.test", "oidc.discovery_url", regexp.MustCompile(`/\.well-known/openid-configuration$`)),
+					func(s *terraform.State) error {
+						id, err := testAccApplicationID(s)
+						if err != nil {
+							return err
+						}
+						app, _ := srv.Application(id)
+						if app.Type != 2 || app.OidcParams == nil || app.OidcParams.AccessTokenLifetime != 900 {
+							return fmt.Errorf("application on server has type %d and OIDC settings %+v", app.Type, app.OidcParams)
+						}
+						return nil
+					},
+					testAccCheckRequestBody(srv, "POST", "/api/v1/application", `{"attr_mapping":null,"branding_id":null,"name":"portal",`+
+						`"oidc_params":{"access_token_lifetime":900,"grant_types":["authorization_code","refresh_token"],"id_token_lifetime":3600,`+
+						`"redirect_uris":["https://portal.example.com/callback"],"refresh_token_lifetime":86400,"scopes":["email","openid"]},`+
+						`"realm_id":"`+srv.DefaultRealmID()+`","ttl":0,"type":2}`),
+				),
+			},
+			// the client secret is only returned on create
+			{
+				ResourceName:            "fortitokencloud_application.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"oidc.client_secret"},
+			},
+			// updates keep the generated credentials
+			{
+				Config: srv.ProviderConfig() + testAccApplicationOidcConfig(1800),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_application.test", "oidc.access_token_lifetime", "1800"),
+					resource.TestCheckResourceAttrSet("fortitokencloud_application.test", "oidc.client_secret"),
+					// realm and type cannot change, the update leaves them out
+					func(s *terraform.State) error {
+						id, err := testAccApplicationID(s)
+						if err != nil {
+							return err
+						}
+						return testAccCheckRequestBody(srv, "PUT", "/api/v1/application/"+id, `{"attr_mapping":null,"branding_id":null,"name":"portal",`+
+							`"oidc_params":{"access_token_lifetime":1800,"grant_types":["authorization_code","refresh_token"],"id_token_lifetime":3600,`+
+							`"redirect_uris":["https://portal.example.com/callback"],"refresh_token_lifetime":86400,"scopes":["email","openid"]},`+
+							`"ttl":0}`)(s)
+					},
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckRequestBody checks the body of the last method request to path received by srv.
+func testAccCheckRequestBody(srv *ftctest.Server, method, path, want string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if got := srv.LastRequestBody(method, path); got != want {
+			return fmt.Errorf("%s %s body =\n%s\nwant\n%s", method, path, got, want)
+		}
+		return nil
+	}
+}
+
+func TestAccApplicationResourceOidcValidation(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	config := func(app_type, settings string) string {
+		return srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_application" "test" {
+  name     = "portal"
+  type     = %q
+  realm_id = "realm"
+  %s
+}
+`, app_type, settings)
+	}
+	oidc := func(settings string) string {
+		return "oidc {\n    " + settings + "\n  }"
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"unknown type", config("ws-fed", ""), `type must be "saml" or "oidc", got "ws-fed"`},
+		{"missing oidc block", config("oidc", ""), `An oidc block is required when type = "oidc"`},
+		{"oidc block on SAML", config("saml", oidc(`redirect_uris = ["https://portal.example.com/callback"]`)), `The oidc block only applies to OIDC applications`},
+		{"SAML attribute on OIDC", config("oidc", `sp_entity_id = "https://sp.example.com"`+"\n  "+oidc(`redirect_uris = ["https://portal.example.com/callback"]`)), `sp_entity_id only applies to SAML applications`},
+		{"missing openid scope", config("oidc", oidc(`redirect_uris = ["https://portal.example.com/callback"]`+"\n    "+`scopes = ["email"]`)), `scopes must include openid`},
+		{"unknown grant type", config("oidc", oidc(`grant_types = ["password"]`)), `grant_types must only contain`},
+		{"missing redirect URIs", config("oidc", oidc(`scopes = ["openid"]`)), `redirect_uris is required for the authorization_code grant`},
+		{"insecure redirect URI", config("oidc", oidc(`redirect_uris = ["http://portal.example.com/callback"]`)), `must use https,\s+or http on localhost`},
+		{"token lifetime", config("oidc", oidc(`redirect_uris = ["https://portal.example.com/callback"]`+"\n    "+`id_token_lifetime = 30`)), `id_token_lifetime must be between 60 and 2592000\s+seconds, got 30`},
+	}
+	steps := make([]resource.TestStep, 0, len(tests))
+	for _, tt := range tests {
+		steps = append(steps, resource.TestStep{
+			Config:      tt.config,
+			ExpectError: regexp.MustCompile(tt.wantErr),
+		})
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps:                    steps,
+	})
+}
+
+func TestFormatAppObjType(t *testing.T) {
+	plan := applicationResourceModel{
+		Name:        types.StringValue("portal"),
+		Type:        types.StringValue("saml"),
+		RealmID:     types.StringValue("realm"),
+		TTL:         types.Int64Value(3600),
+		AttrMapping: types.MapNull(types.StringType),
+	}
+	// SAML applications are created without a type, as before OIDC support
+	obj, _ := formatAppObj(plan, true)
+	if app_type, ok := (*obj)["type"]; ok {
+		t.Errorf("SAML create payload has type %v", app_type)
+	}
+
+	plan.Type = types.StringValue("oidc")
+	plan.Oidc = &applicationOidcModel{
+		RedirectURIs:         []types.String{types.StringValue("https://portal.example.com/callback")},
+		Scopes:               []types.String{types.StringValue("openid")},
+		GrantTypes:           []types.String{types.StringValue("authorization_code")},
+		AccessTokenLifetime:  types.Int64Value(900),
+		IDTokenLifetime:      types.Int64Value(3600),
+		RefreshTokenLifetime: types.Int64Value(86400),
+	}
+	obj, _ = formatAppObj(plan, true)
+	got, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"attr_mapping":null,"branding_id":null,"name":"portal",` +
+		`"oidc_params":{"access_token_lifetime":900,"grant_types":["authorization_code"],"id_token_lifetime":3600,` +
+		`"redirect_uris":["https://portal.example.com/callback"],"refresh_token_lifetime":86400,"scopes":["openid"]},` +
+		`"realm_id":"realm","ttl":3600,"type":2}`
+	if string(got) != want {
+		t.Errorf("OIDC create payload =\n%s\nwant\n%s", got, want)
+	}
+}
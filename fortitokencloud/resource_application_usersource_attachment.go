@@ -0,0 +1,37 @@
+package fortitokencloud
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// applicationUserSources is the list of user sources attached to an application.
+var applicationUserSources = &attachment{
+	typeName:    "_application_usersource_attachment",
+	description: "Attaches a single user source to an application, leaving other attachments alone.",
+	mapping:     "app user source",
+	parent:      attachmentSide{attribute: "application_id", name: "application"},
+	child:       attachmentSide{attribute: "user_source_id", name: "user source"},
+	locks:       &applicationLocks,
+	get: func(ctx context.Context, client *ftc_client.Client, app_id string) ([]string, error) {
+		application, err := client.GetApplicationWithContext(ctx, app_id)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, 0, len(application.UserSources))
+		for _, usersource := range application.UserSources {
+			ids = append(ids, usersource.ID)
+		}
+		return ids, nil
+	},
+	set: func(ctx context.Context, client *ftc_client.Client, app_id string, ids []string) error {
+		_, err := client.UpdateApplicationUserSourceWithContext(ctx, app_id, map[string][]string{"user_source_ids": ids})
+		return err
+	},
+}
+
+// NewApplicationUserSourceAttachmentResource is a helper function to simplify the provider implementation.
+func NewApplicationUserSourceAttachmentResource() resource.Resource {
+	return &attachmentResource{attachment: applicationUserSources}
+}
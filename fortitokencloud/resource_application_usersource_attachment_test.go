@@ -0,0 +1,208 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"terraform-provider-fortitokencloud/sdk/ftctest"
+)
+
+// testAccApplicationUserSourceAttachmentConfig attaches the named user sources to an
+// application that leaves user_source_ids unset.
+func testAccApplicationUserSourceAttachmentConfig(attached ...string) string {
+	config := `
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_application" "test" {
+  name     = "app"
+  realm_id = data.fortitokencloud_realm.test.id
+}
+`
+	for _, name := range []string{"okta", "azure"} {
+		config += fmt.Sprintf(`
+resource "fortitokencloud_usersource" %[1]q {
+  name     = %[1]q
+  type     = "oidc"
+  realm_id = data.fortitokencloud_realm.test.id
+
+  oidc {
+    auth_uri      = "https://%[1]s.example.com/oauth2/v1/authorize"
+    token_uri     = "https://%[1]s.example.com/oauth2/v1/token"
+    client_id     = %[1]q
+    client_secret = "secret"
+  }
+}
+`, name)
+	}
+	for _, name := range attached {
+		config += fmt.Sprintf(`
+resource "fortitokencloud_application_usersource_attachment" %[1]q {
+  application_id = fortitokencloud_application.test.id
+  user_source_id = fortitokencloud_usersource.%[1]s.id
+}
+`, name)
+	}
+	return config
+}
+
+// testAccCheckApplicationUserSources checks the user sources attached on the server by
+// their resource names.
+func testAccCheckApplicationUserSources(srv *ftctest.Server, names ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		id, err := testAccApplicationID(s)
+		if err != nil {
+			return err
+		}
+		app, ok := srv.Application(id)
+		if !ok {
+			return fmt.Errorf("application %s does not exist", id)
+		}
+		want := make([]string, 0, len(names))
+		for _, name := range names {
+			rs, ok := s.RootModule().Resources["fortitokencloud_usersource."+name]
+			if !ok {
+				return fmt.Errorf("fortitokencloud_usersource.%s not found in state", name)
+			}
+			want = append(want, rs.Primary.ID)
+		}
+		got := make([]string, 0, len(app.UserSources))
+		for _, usersource := range app.UserSources {
+			got = append(got, usersource.ID)
+		}
+		sort.Strings(want)
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			return fmt.Errorf("user sources on server are %v, want %v", got, want)
+		}
+		return nil
+	}
+}
+
+func TestAccApplicationUserSourceAttachmentResource(t *testing.T) {
+	srv, client := testAccServer(t)
+	var app_id, azure_id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckApplicationDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccApplicationUserSourceAttachmentConfig("okta"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("fortitokencloud_application_usersource_attachment.okta", "application_id", "fortitokencloud_application.test", "id"),
+					resource.TestCheckResourceAttrPair("fortitokencloud_application_usersource_attachment.okta", "user_source_id", "fortitokencloud_usersource.okta", "id"),
+					testAccCheckApplicationUserSources(srv, "okta"),
+				),
+			},
+			// a second attachment keeps the first, and the application leaves both alone
+			{
+				Config: srv.ProviderConfig() + testAccApplicationUserSourceAttachmentConfig("okta", "azure"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckApplicationUserSources(srv, "okta", "azure"),
+					func(s *terraform.State) (err error) {
+						app_id, err = testAccApplicationID(s)
+						azure_id = s.RootModule().Resources["fortitokencloud_usersource.azure"].Primary.ID
+						return err
+					},
+				),
+			},
+			{
+				ResourceName:      "fortitokencloud_application_usersource_attachment.okta",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  "fortitokencloud_application_usersource_attachment.okta",
+				ImportState:   true,
+				ImportStateId: "missing-slash",
+				ExpectError:   regexp.MustCompile(`Unexpected Import Identifier`),
+			},
+			// a user source detached outside Terraform is attached again
+			{
+				PreConfig: func() {
+					if _, err := client.UpdateApplicationUserSourceWithContext(context.Background(), app_id, map[string][]string{"user_source_ids": {azure_id}}); err != nil {
+						t.Fatalf("detaching user source: %s", err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccApplicationUserSourceAttachmentConfig("okta", "azure"),
+				Check:  testAccCheckApplicationUserSources(srv, "okta", "azure"),
+			},
+			// removing an attachment detaches only that user source
+			{
+				Config: srv.ProviderConfig() + testAccApplicationUserSourceAttachmentConfig("azure"),
+				Check:  testAccCheckApplicationUserSources(srv, "azure"),
+			},
+		},
+	})
+}
+
+func TestAccApplicationUserSourceAttachmentResourceErrors(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + `
+resource "fortitokencloud_application_usersource_attachment" "test" {
+  application_id = "missing"
+  user_source_id = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Could not attach user source missing to application\s+missing`),
+			},
+		},
+	})
+}
+
+func TestAccApplicationUserSourcesChangedDuringApply(t *testing.T) {
+	srv, _ := testAccServer(t)
+	config := testAccApplicationUserSourceAttachmentConfig()
+	withUserSources := func(user_source_ids, extra string) string {
+		return strings.Replace(config, "realm_id = data.fortitokencloud_realm.test.id\n}", `realm_id = data.fortitokencloud_realm.test.id
+
+  user_source_ids = `+user_source_ids+`
+}`+extra, 1)
+	}
+	// the attachment looks the application up by name so that it can be applied first and
+	// change the user sources between the plan and the application update
+	racing := withUserSources(`[]
+  depends_on      = [fortitokencloud_application_usersource_attachment.azure]`, `
+
+data "fortitokencloud_applications" "all" {}
+
+resource "fortitokencloud_application_usersource_attachment" "azure" {
+  application_id = one([for app in data.fortitokencloud_applications.all.apps : app.id if app.name == "app"])
+  user_source_id = fortitokencloud_usersource.azure.id
+}`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + withUserSources("[fortitokencloud_usersource.okta.id]", ""),
+				Check:  testAccCheckApplicationUserSources(srv, "okta"),
+			},
+			// the application only detaches what it planned to, azure stays attached and
+			// shows up as drift in the next plan
+			{
+				Config:             srv.ProviderConfig() + racing,
+				Check:              testAccCheckApplicationUserSources(srv, "azure"),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: srv.ProviderConfig() + withUserSources("[]", ""),
+				Check:  testAccCheckApplicationUserSources(srv),
+			},
+		},
+	})
+}
@@ -0,0 +1,228 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"slices"
+	"strings"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// attachment describes a list of IDs an FTC object holds, such as the user sources of an
+// application, and how to read and replace it.
+type attachment struct {
+	// typeName is the resource type name without the provider prefix.
+	typeName    string
+	description string
+	// mapping names the attachment in error summaries.
+	mapping string
+	parent  attachmentSide
+	child   attachmentSide
+	// locks serialises changes to the list of one parent.
+	locks *keyedMutex
+	get   func(ctx context.Context, client *ftc_client.Client, parent_id string) ([]string, error)
+	set   func(ctx context.Context, client *ftc_client.Client, parent_id string, ids []string) error
+}
+
+// attachmentSide is the parent or child end of an attachment.
+type attachmentSide struct {
+	// attribute holds the ID in the resource schema, like application_id.
+	attribute string
+	// name is used in messages, like application.
+	name string
+}
+
+// update reads the IDs attached to parent_id, applies change and writes the list back when it
+// differs. The read and write happen under the parent lock so attachments to the same parent
+// do not overwrite each other.
+func (a *attachment) update(ctx context.Context, client *ftc_client.Client, parent_id string, change func([]string) []string) error {
+	unlock := a.locks.Lock(parent_id)
+	defer unlock()
+
+	current, err := a.get(ctx, client, parent_id)
+	if err != nil {
+		return err
+	}
+
+	updated := change(current)
+	if sameIDs(updated, current) {
+		return nil
+	}
+	return a.set(ctx, client, parent_id, updated)
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &attachmentResource{}
+	_ resource.ResourceWithConfigure   = &attachmentResource{}
+	_ resource.ResourceWithImportState = &attachmentResource{}
+)
+
+// attachmentResource manages a single entry of the list described by attachment, leaving the
+// other entries alone.
+type attachmentResource struct {
+	attachment *attachment
+	client     *ftc_client.Client
+}
+
+// ids returns the parent and child IDs held in state or plan.
+func (r *attachmentResource) ids(ctx context.Context, get func(context.Context, path.Path, interface{}) diag.Diagnostics) (string, string, diag.Diagnostics) {
+	var parent_id, child_id types.String
+	diags := get(ctx, path.Root(r.attachment.parent.attribute), &parent_id)
+	diags.Append(get(ctx, path.Root(r.attachment.child.attribute), &child_id)...)
+	return parent_id.ValueString(), child_id.ValueString(), diags
+}
+
+// Metadata returns the resource type name.
+func (r *attachmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.attachment.typeName
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *attachmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.client
+}
+
+// Schema defines the schema for the resource.
+func (r *attachmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: r.attachment.description,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: r.attachment.parent.attribute + " and " + r.attachment.child.attribute + " separated by a slash.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			r.attachment.parent.attribute: schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			r.attachment.child.attribute: schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// Create a new resource.
+func (r *attachmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	parent_id, child_id, diags := r.ids(ctx, req.Plan.GetAttribute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.attachment.update(ctx, r.client, parent_id, func(ids []string) []string {
+		if slices.Contains(ids, child_id) {
+			return ids
+		}
+		return append(ids, child_id)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating "+r.attachment.mapping+" mapping",
+			"Could not attach "+r.attachment.child.name+" "+child_id+" to "+r.attachment.parent.name+" "+parent_id+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parent_id+"/"+child_id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(r.attachment.parent.attribute), parent_id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(r.attachment.child.attribute), child_id)...)
+}
+
+// Read resource information.
+func (r *attachmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	parent_id, child_id, diags := r.ids(ctx, req.State.GetAttribute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ids, err := r.attachment.get(ctx, r.client, parent_id)
+	if err != nil && !ftc_client.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Reading "+r.attachment.parent.name,
+			"Could not read "+r.attachment.parent.name+" ID "+parent_id+": "+err.Error(),
+		)
+		return
+	}
+	// the parent was deleted or the child detached outside Terraform
+	if !slices.Contains(ids, child_id) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+}
+
+// Update is never called with changes, both attributes force a new attachment.
+func (r *attachmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *attachmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	parent_id, child_id, diags := r.ids(ctx, req.State.GetAttribute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Detach the child, a 404 means the parent is already gone
+	err := r.attachment.update(ctx, r.client, parent_id, func(ids []string) []string {
+		return slices.DeleteFunc(slices.Clone(ids), func(id string) bool { return id == child_id })
+	})
+	if err != nil && !ftc_client.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting "+r.attachment.mapping+" mapping",
+			"Could not detach "+r.attachment.child.name+" "+child_id+" from "+r.attachment.parent.name+" "+parent_id+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *attachmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import IDs have the form <parent_id>/<child_id>
+	parent_id, child_id, ok := strings.Cut(req.ID, "/")
+	if !ok || parent_id == "" || child_id == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import ID of the form <%s>/<%s>, got: %q", r.attachment.parent.attribute, r.attachment.child.attribute, req.ID),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(r.attachment.parent.attribute), parent_id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(r.attachment.child.attribute), child_id)...)
+}
@@ -0,0 +1,53 @@
+package fortitokencloud
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+func TestAttachmentUpdate(t *testing.T) {
+	stored := []string{"a", "b"}
+	writes := 0
+	a := &attachment{
+		locks: &keyedMutex{},
+		get: func(_ context.Context, _ *ftc_client.Client, parent_id string) ([]string, error) {
+			if parent_id == "missing" {
+				return nil, errors.New("not found")
+			}
+			return slices.Clone(stored), nil
+		},
+		set: func(_ context.Context, _ *ftc_client.Client, _ string, ids []string) error {
+			writes++
+			stored = ids
+			return nil
+		},
+	}
+	ctx := context.Background()
+
+	// an unchanged list, in any order, is not written back
+	if err := a.update(ctx, nil, "parent", func(ids []string) []string { return []string{"b", "a"} }); err != nil {
+		t.Fatal(err)
+	}
+	if writes != 0 {
+		t.Errorf("unchanged list was written %d times", writes)
+	}
+
+	if err := a.update(ctx, nil, "parent", func(ids []string) []string { return append(ids, "c") }); err != nil {
+		t.Fatal(err)
+	}
+	if writes != 1 || !slices.Equal(stored, []string{"a", "b", "c"}) {
+		t.Errorf("after adding c: %d writes, stored %v", writes, stored)
+	}
+
+	// a failed read writes nothing
+	if err := a.update(ctx, nil, "missing", func(ids []string) []string { return nil }); err == nil {
+		t.Error("update of a missing parent succeeded")
+	}
+	if writes != 1 {
+		t.Errorf("failed read was followed by a write")
+	}
+}
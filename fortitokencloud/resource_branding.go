@@ -0,0 +1,392 @@
+package fortitokencloud
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_               resource.Resource                   = &brandingResource{}
+	_               resource.ResourceWithConfigure      = &brandingResource{}
+	_               resource.ResourceWithImportState    = &brandingResource{}
+	_               resource.ResourceWithValidateConfig = &brandingResource{}
+	_               resource.ResourceWithModifyPlan     = &brandingResource{}
+	branding_colors                                     = []string{"primary_color", "background_color", "text_color"}
+)
+
+// NewBrandingResource is a helper function to simplify the provider implementation.
+func NewBrandingResource() resource.Resource {
+	return &brandingResource{}
+}
+
+// brandingResource is the resource implementation.
+type brandingResource struct {
+	client *ftc_client.Client
+}
+
+func formatBrandingObj(plan brandingResourceModel, create bool) *map[string]interface{} {
+	obj := make(map[string]interface{})
+	obj["name"] = plan.Name.ValueString()
+	if create {
+		obj["realm_id"] = plan.RealmID.ValueString()
+	}
+	obj["title"] = plan.Title.ValueString()
+	obj["primary_color"] = plan.PrimaryColor.ValueString()
+	obj["background_color"] = plan.BackgroundColor.ValueString()
+	obj["text_color"] = plan.TextColor.ValueString()
+	obj["footer_text"] = plan.FooterText.ValueString()
+	return &obj
+}
+
+// setBrandingLogo adds the logo read from logo_path to obj, a null path removes the logo. The
+// file must still have the content hashed into logo_hash at plan time.
+func setBrandingLogo(obj *map[string]interface{}, logo_path, logo_hash types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if logo_path.IsNull() {
+		(*obj)["logo"] = nil
+		return diags
+	}
+	logo, err := readLogo(logo_path.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("logo_path"),
+			"Invalid logo",
+			"Could not read logo: "+err.Error(),
+		)
+		return diags
+	}
+	if hash := hashLogo(logo); !logo_hash.IsUnknown() && hash != logo_hash.ValueString() {
+		diags.AddAttributeError(
+			path.Root("logo_path"),
+			"Logo changed since plan",
+			fmt.Sprintf("%s was modified after the plan was made, its SHA-256 is now %s instead of the planned %s. "+
+				"The logo was not uploaded; run terraform apply again to plan the current file.", logo_path.ValueString(), hash, logo_hash.ValueString()),
+		)
+		return diags
+	}
+	(*obj)["logo"] = base64.StdEncoding.EncodeToString(logo)
+	return diags
+}
+
+// Metadata returns the resource type name.
+func (r *brandingResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_branding"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *brandingResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.client
+}
+
+// Schema defines the schema for the resource.
+func (r *brandingResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Login page branding of a realm, referenced by branding_id on applications.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"realm_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"title": schema.StringAttribute{
+				Description: "Title shown on the login page.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+			},
+			"primary_color": schema.StringAttribute{
+				Description: "Button and link color, as #RRGGBB.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+			},
+			"background_color": schema.StringAttribute{
+				Description: "Page background color, as #RRGGBB.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+			},
+			"text_color": schema.StringAttribute{
+				Description: "Text color, as #RRGGBB.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+			},
+			"footer_text": schema.StringAttribute{
+				Description: "Text shown at the bottom of the login page.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+			},
+			"logo_path": schema.StringAttribute{
+				Description: "Path of a local PNG, JPEG, GIF or SVG file uploaded as the logo, at most 512 KiB.",
+				Optional:    true,
+			},
+			"logo_hash": schema.StringAttribute{
+				Description: "SHA-256 of the logo content, a change uploads the logo again.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// brandingResourceModel maps the resource schema data.
+type brandingResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	RealmID         types.String `tfsdk:"realm_id"`
+	Title           types.String `tfsdk:"title"`
+	PrimaryColor    types.String `tfsdk:"primary_color"`
+	BackgroundColor types.String `tfsdk:"background_color"`
+	TextColor       types.String `tfsdk:"text_color"`
+	FooterText      types.String `tfsdk:"footer_text"`
+	LogoPath        types.String `tfsdk:"logo_path"`
+	LogoHash        types.String `tfsdk:"logo_hash"`
+}
+
+// brandingResourceModelFrom builds the resource model from a branding returned by FTC. The logo
+// path is carried over from prior, FTC only keeps the logo content.
+func brandingResourceModelFrom(branding *ftc_client.Branding, prior brandingResourceModel) brandingResourceModel {
+	return brandingResourceModel{
+		ID:              types.StringValue(branding.ID),
+		Name:            types.StringValue(branding.Name),
+		RealmID:         types.StringValue(branding.RealmID),
+		Title:           types.StringValue(branding.Title),
+		PrimaryColor:    types.StringValue(branding.PrimaryColor),
+		BackgroundColor: types.StringValue(branding.BackgroundColor),
+		TextColor:       types.StringValue(branding.TextColor),
+		FooterText:      types.StringValue(branding.FooterText),
+		LogoPath:        prior.LogoPath,
+		LogoHash:        types.StringValue(brandingLogoHash(branding.Logo)),
+	}
+}
+
+// ValidateConfig checks the colors at plan time.
+func (r *brandingResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	for _, attribute := range branding_colors {
+		var color types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(attribute), &color)...)
+		if color.IsNull() || color.IsUnknown() || color.ValueString() == "" {
+			continue
+		}
+		if !hexColorPattern.MatchString(color.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Invalid color",
+				fmt.Sprintf("%s must be a #RRGGBB color, got %q.", attribute, color.ValueString()),
+			)
+		}
+	}
+}
+
+// ModifyPlan hashes the logo file so a changed file plans an upload.
+func (r *brandingResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to hash on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var logo_path types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("logo_path"), &logo_path)...)
+	if resp.Diagnostics.HasError() || logo_path.IsUnknown() {
+		return
+	}
+
+	logo_hash := ""
+	if !logo_path.IsNull() {
+		logo, err := readLogo(logo_path.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("logo_path"),
+				"Invalid logo",
+				"Could not read logo: "+err.Error(),
+			)
+			return
+		}
+		logo_hash = hashLogo(logo)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("logo_hash"), logo_hash)...)
+}
+
+// Create a new resource.
+func (r *brandingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan brandingResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	obj := formatBrandingObj(plan, true)
+	resp.Diagnostics.Append(setBrandingLogo(obj, plan.LogoPath, plan.LogoHash)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new branding
+	branding, err := r.client.CreateBrandingWithContext(ctx, obj)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating branding",
+			"Could not create branding, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan = brandingResourceModelFrom(branding, plan)
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *brandingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state brandingResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	branding, err := r.client.GetBrandingWithContext(ctx, state.ID.ValueString())
+	if ftc_client.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading branding",
+			"Could not read branding ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	state = brandingResourceModelFrom(branding, state)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *brandingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan brandingResourceModel
+	var state brandingResourceModel
+	var branding *ftc_client.Branding
+	var err error
+	diags := req.Plan.Get(ctx, &plan)
+	req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	create := plan.ID.ValueString() == ""
+	obj := formatBrandingObj(plan, create)
+
+	// the logo is only uploaded when its content changed
+	if create || !plan.LogoHash.Equal(state.LogoHash) {
+		resp.Diagnostics.Append(setBrandingLogo(obj, plan.LogoPath, plan.LogoHash)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if create {
+		// Create new branding if not found
+		branding, err = r.client.CreateBrandingWithContext(ctx, obj)
+	} else {
+		// Update existing branding
+		branding, err = r.client.UpdateBrandingWithContext(ctx, plan.ID.ValueString(), obj)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating branding",
+			"Could not update branding, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan = brandingResourceModelFrom(branding, plan)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *brandingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state brandingResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete existing branding, a 404 means it is already gone
+	if state.ID.ValueString() != "" {
+		err := r.client.DeleteBrandingWithContext(ctx, state.ID.ValueString())
+		if err != nil && !ftc_client.IsNotFound(err) {
+			resp.Diagnostics.AddError(
+				"Error Deleting branding",
+				"Could not delete branding, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
+func (r *brandingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to id attribute
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
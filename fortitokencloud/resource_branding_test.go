@@ -0,0 +1,207 @@
+package fortitokencloud
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"terraform-provider-fortitokencloud/sdk/ftctest"
+)
+
+// testAccBrandingConfig configures a branding, with a logo when logo_path is set.
+func testAccBrandingConfig(color, logo_path string) string {
+	logo := ""
+	if logo_path != "" {
+		logo = fmt.Sprintf("logo_path = %q", logo_path)
+	}
+	return fmt.Sprintf(`
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_branding" "test" {
+  name          = "corporate"
+  realm_id      = data.fortitokencloud_realm.test.id
+  title         = "Sign in"
+  primary_color = %q
+  %s
+}
+`, color, logo)
+}
+
+// testAccCheckBrandingLogo checks the logo stored by the server is content, nil for no logo.
+func testAccCheckBrandingLogo(srv *ftctest.Server, content []byte) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["fortitokencloud_branding.test"]
+		if !ok {
+			return fmt.Errorf("fortitokencloud_branding.test not found in state")
+		}
+		branding, ok := srv.Branding(rs.Primary.ID)
+		if !ok {
+			return fmt.Errorf("branding %s does not exist", rs.Primary.ID)
+		}
+		want := ""
+		if content != nil {
+			want = base64.StdEncoding.EncodeToString(content)
+		}
+		if branding.Logo != want {
+			return fmt.Errorf("logo on server is %d base64 characters, want %d", len(branding.Logo), len(want))
+		}
+		return nil
+	}
+}
+
+func testAccCheckBrandingDestroy(srv *ftctest.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "fortitokencloud_branding" {
+				continue
+			}
+			if _, ok := srv.Branding(rs.Primary.ID); ok {
+				return fmt.Errorf("branding %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccBrandingResource(t *testing.T) {
+	srv, client := testAccServer(t)
+	logo_path, logo := testLogo(t, 1)
+	_, new_logo := testLogo(t, 2)
+	var branding_id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBrandingDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccBrandingConfig("#1A2B3C", logo_path),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_branding.test", "title", "Sign in"),
+					resource.TestCheckResourceAttr("fortitokencloud_branding.test", "primary_color", "#1A2B3C"),
+					resource.TestCheckResourceAttr("fortitokencloud_branding.test", "background_color", ""),
+					resource.TestCheckResourceAttr("fortitokencloud_branding.test", "logo_hash", hashLogo(logo)),
+					testAccCheckBrandingLogo(srv, logo),
+				),
+			},
+			{
+				ResourceName:            "fortitokencloud_branding.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"logo_path"},
+			},
+			// a new file at the same path is uploaded again
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(logo_path, new_logo, 0o600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccBrandingConfig("#1A2B3C", logo_path),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_branding.test", "logo_hash", hashLogo(new_logo)),
+					testAccCheckBrandingLogo(srv, new_logo),
+				),
+			},
+			// removing logo_path removes the logo
+			{
+				Config: srv.ProviderConfig() + testAccBrandingConfig("#4D5E6F", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_branding.test", "primary_color", "#4D5E6F"),
+					resource.TestCheckResourceAttr("fortitokencloud_branding.test", "logo_hash", ""),
+					testAccCheckBrandingLogo(srv, nil),
+					func(s *terraform.State) error {
+						branding_id = s.RootModule().Resources["fortitokencloud_branding.test"].Primary.ID
+						return nil
+					},
+				),
+			},
+			// a branding deleted outside Terraform is created again
+			{
+				PreConfig: func() {
+					if err := client.DeleteBrandingWithContext(context.Background(), branding_id); err != nil {
+						t.Fatalf("deleting branding: %s", err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccBrandingConfig("#4D5E6F", ""),
+				Check: func(s *terraform.State) error {
+					if id := s.RootModule().Resources["fortitokencloud_branding.test"].Primary.ID; id == branding_id {
+						return fmt.Errorf("branding %s was not created again", id)
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:  "fortitokencloud_branding.test",
+				ImportState:   true,
+				ImportStateId: "missing",
+				ExpectError:   regexp.MustCompile(`Cannot import non-existent remote object`),
+			},
+		},
+	})
+}
+
+func TestAccBrandingResourceValidation(t *testing.T) {
+	srv, _ := testAccServer(t)
+	text_path := filepath.Join(t.TempDir(), "logo.png")
+	if err := os.WriteFile(text_path, []byte("not an image"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      srv.ProviderConfig() + testAccBrandingConfig("blue", ""),
+				ExpectError: regexp.MustCompile(`(?s)Invalid color.*primary_color must be a #RRGGBB color`),
+			},
+			{
+				Config:      srv.ProviderConfig() + testAccBrandingConfig("#1A2B3C", text_path),
+				ExpectError: regexp.MustCompile(`(?s)Invalid logo.*is not a PNG,\s+JPEG,\s+GIF or SVG\s+image`),
+			},
+		},
+	})
+}
+
+func TestSetBrandingLogo(t *testing.T) {
+	logo_path, logo := testLogo(t, 1)
+	_, new_logo := testLogo(t, 2)
+
+	// the planned content is uploaded as is
+	obj := map[string]interface{}{}
+	if diags := setBrandingLogo(&obj, types.StringValue(logo_path), types.StringValue(hashLogo(logo))); diags.HasError() {
+		t.Fatalf("setBrandingLogo: %v", diags)
+	}
+	if obj["logo"] != base64.StdEncoding.EncodeToString(logo) {
+		t.Errorf("uploaded logo is not the file content")
+	}
+
+	// a file replaced after the plan is not uploaded
+	if err := os.WriteFile(logo_path, new_logo, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	obj = map[string]interface{}{}
+	diags := setBrandingLogo(&obj, types.StringValue(logo_path), types.StringValue(hashLogo(logo)))
+	if !diags.HasError() || diags[0].Summary() != "Logo changed since plan" || !strings.Contains(diags[0].Detail(), hashLogo(new_logo)) {
+		t.Errorf("setBrandingLogo of a changed file = %v", diags)
+	}
+	if _, ok := obj["logo"]; ok {
+		t.Error("changed logo was added to the payload")
+	}
+
+	// no logo_path removes the logo
+	obj = map[string]interface{}{}
+	if diags := setBrandingLogo(&obj, types.StringNull(), types.StringValue("")); diags.HasError() || obj["logo"] != nil {
+		t.Errorf("setBrandingLogo without a path = %v, %v", obj, diags)
+	}
+}
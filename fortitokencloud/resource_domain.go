@@ -9,7 +9,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"strings"
 	ftc_client "terraform-provider-fortitokencloud/sdk"
 )
 
@@ -36,11 +35,7 @@ func formatDomainObj(plan domainResourceModel, create bool) *map[string]interfac
 	if create {
 		obj["realm_id"] = plan.RealmID.ValueString()
 	}
-	if plan.UserSourceID.ValueString() == "" {
-		obj["user_source_id"] = nil
-	} else {
-		obj["user_source_id"] = plan.UserSourceID.ValueString()
-	}
+	// user_source_id is left out, the user source and attachment resources own the mapping
 	return &obj
 }
 
@@ -55,18 +50,18 @@ func (r *domainResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	client, ok := req.ProviderData.(*ftc_client.Client)
+	data, ok := req.ProviderData.(*resourceData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *ftc.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.client
 }
 
 // Schema defines the schema for the resource.
@@ -86,8 +81,9 @@ func (r *domainResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				Required: true,
 			},
 			"user_source_id": schema.StringAttribute{
-				Computed: true,
-				Required: false,
+				Description: "ID of the user source the domain is attached to, set through fortitokencloud_usersource or fortitokencloud_usersource_domain_attachment.",
+				Computed:    true,
+				Required:    false,
 			},
 		},
 	}
@@ -114,7 +110,7 @@ func (r *domainResource) Create(ctx context.Context, req resource.CreateRequest,
 	obj := formatDomainObj(plan, true)
 
 	// Create new domain
-	domain, err := r.client.CreateDomain(obj)
+	domain, err := r.client.CreateDomainWithContext(ctx, obj)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating domain",
@@ -149,31 +145,25 @@ func (r *domainResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	// if resource has been deleted upstream, reset state
-	if state.ID.ValueString() == "" {
-		state = domainResourceModel{}
-	} else {
-		// Get refreshed user source
-		usersource, err := r.client.GetDomain(state.ID.ValueString())
-		if err != nil {
-			if !strings.Contains(err.Error(), "status: 404") {
-				resp.Diagnostics.AddError(
-					"Error Reading domain",
-					"Could not read domain ID "+state.ID.ValueString()+": "+err.Error(),
-				)
-				return
-			}
-			state = domainResourceModel{}
-		} else {
-
-			// Overwrite items with refreshed state
-			state = domainResourceModel{
-				ID:           types.StringValue(usersource.ID),
-				Name:         types.StringValue(usersource.Name),
-				RealmID:      types.StringValue(usersource.RealmID),
-				UserSourceID: types.StringValue(usersource.UserSourceID),
-			}
-		}
+	domain, err := r.client.GetDomainWithContext(ctx, state.ID.ValueString())
+	if ftc_client.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading domain",
+			"Could not read domain ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	state = domainResourceModel{
+		ID:           types.StringValue(domain.ID),
+		Name:         types.StringValue(domain.Name),
+		RealmID:      types.StringValue(domain.RealmID),
+		UserSourceID: types.StringValue(domain.UserSourceID),
 	}
 
 	// Set refreshed state
@@ -200,9 +190,9 @@ func (r *domainResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	if plan.ID.ValueString() == "" {
 		obj = formatDomainObj(plan, true)
-		domain, err = r.client.CreateDomain(obj)
+		domain, err = r.client.CreateDomainWithContext(ctx, obj)
 	} else {
-		domain, err = r.client.UpdateDomain(plan.ID.ValueString(), obj)
+		domain, err = r.client.UpdateDomainWithContext(ctx, plan.ID.ValueString(), obj)
 	}
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -236,10 +226,10 @@ func (r *domainResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	// Delete existing domain
+	// Delete existing domain, a 404 means it is already gone
 	if state.ID.ValueString() != "" {
-		err := r.client.DeleteDomain(state.ID.ValueString())
-		if err != nil {
+		err := r.client.DeleteDomainWithContext(ctx, state.ID.ValueString())
+		if err != nil && !ftc_client.IsNotFound(err) {
 			resp.Diagnostics.AddError(
 				"Error Deleting domain",
 				"Could not delete domain, unexpected error: "+err.Error(),
@@ -0,0 +1,179 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"terraform-provider-fortitokencloud/sdk/ftctest"
+)
+
+func testAccDomainConfig(name string) string {
+	return fmt.Sprintf(`
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_domain" "test" {
+  name     = %q
+  realm_id = data.fortitokencloud_realm.test.id
+}
+`, name)
+}
+
+// testAccCheckDomainExists checks the domain in state exists on the server with the given name.
+func testAccCheckDomainExists(srv *ftctest.Server, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["fortitokencloud_domain.test"]
+		if !ok {
+			return fmt.Errorf("fortitokencloud_domain.test not found in state")
+		}
+		domain, ok := srv.Domain(rs.Primary.ID)
+		if !ok {
+			return fmt.Errorf("domain %s does not exist", rs.Primary.ID)
+		}
+		if domain.Name != name {
+			return fmt.Errorf("domain name on server is %q, want %q", domain.Name, name)
+		}
+		return nil
+	}
+}
+
+func testAccCheckDomainDestroy(srv *ftctest.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "fortitokencloud_domain" {
+				continue
+			}
+			if _, ok := srv.Domain(rs.Primary.ID); ok {
+				return fmt.Errorf("domain %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccDomainResource(t *testing.T) {
+	srv, client := testAccServer(t)
+	var domain_id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDomainDestroy(srv),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: srv.ProviderConfig() + testAccDomainConfig("example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_domain.test", "name", "example.com"),
+					resource.TestCheckResourceAttr("fortitokencloud_domain.test", "realm_id", srv.DefaultRealmID()),
+					resource.TestCheckResourceAttr("fortitokencloud_domain.test", "user_source_id", ""),
+					testAccCheckDomainExists(srv, "example.com"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "fortitokencloud_domain.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: srv.ProviderConfig() + testAccDomainConfig("example.org"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_domain.test", "name", "example.org"),
+					testAccCheckDomainExists(srv, "example.org"),
+					func(s *terraform.State) error {
+						domain_id = s.RootModule().Resources["fortitokencloud_domain.test"].Primary.ID
+						return nil
+					},
+				),
+			},
+			// a domain deleted outside Terraform is created again
+			{
+				PreConfig: func() {
+					if err := client.DeleteDomainWithContext(context.Background(), domain_id); err != nil {
+						t.Fatalf("deleting domain: %s", err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccDomainConfig("example.org"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDomainExists(srv, "example.org"),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["fortitokencloud_domain.test"].Primary.ID; id == domain_id {
+							return fmt.Errorf("domain %s was not created again", id)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:  "fortitokencloud_domain.test",
+				ImportState:   true,
+				ImportStateId: "missing",
+				ExpectError:   regexp.MustCompile(`Cannot import non-existent remote object`),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceErrors(t *testing.T) {
+	srv, client := testAccServer(t)
+
+	if _, err := client.CreateDomainWithContext(context.Background(), map[string]interface{}{
+		"name":     "taken.example.com",
+		"realm_id": srv.DefaultRealmID(),
+	}); err != nil {
+		t.Fatalf("unable to create domain: %s", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      srv.ProviderConfig() + testAccDomainConfig("taken.example.com"),
+				ExpectError: regexp.MustCompile(`(?s)Could not create domain.*status: 409`),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+resource "fortitokencloud_domain" "test" {
+  name     = "example.com"
+  realm_id = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Could not create domain.*realm missing does not exist`),
+			},
+		},
+	})
+}
+
+func TestAccDomainResourceReadError(t *testing.T) {
+	srv, _ := testAccServer(t)
+	config := srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_domain" "test" {
+  name     = "example.com"
+  realm_id = %q
+}
+`, srv.DefaultRealmID())
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckDomainDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			// Errors other than 404 fail the refresh instead of dropping the domain
+			{
+				PreConfig:   func() { srv.FailNext(1, http.StatusInternalServerError, "") },
+				Config:      config,
+				ExpectError: regexp.MustCompile(`(?s)Could not read domain ID.*status: 500`),
+			},
+		},
+	})
+}
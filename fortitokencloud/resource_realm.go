@@ -0,0 +1,225 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &realmResource{}
+	_ resource.ResourceWithConfigure   = &realmResource{}
+	_ resource.ResourceWithImportState = &realmResource{}
+)
+
+// NewRealmResource is a helper function to simplify the provider implementation.
+func NewRealmResource() resource.Resource {
+	return &realmResource{}
+}
+
+// realmResource is the resource implementation.
+type realmResource struct {
+	client *ftc_client.Client
+}
+
+func formatRealmObj(plan realmResourceModel) *map[string]interface{} {
+	obj := make(map[string]interface{})
+	obj["name"] = plan.Name.ValueString()
+	return &obj
+}
+
+// Metadata returns the resource type name.
+func (r *realmResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_realm"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *realmResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.client
+}
+
+// Schema defines the schema for the resource.
+func (r *realmResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+		},
+	}
+}
+
+// realmResourceModel maps the resource schema data.
+type realmResourceModel struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+}
+
+// Create a new resource.
+func (r *realmResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan realmResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	obj := formatRealmObj(plan)
+
+	// Create new realm
+	realm, err := r.client.CreateRealmWithContext(ctx, obj)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating realm",
+			"Could not create realm, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan = realmResourceModel{
+		ID:   types.StringValue(realm.ID),
+		Name: types.StringValue(realm.Name),
+	}
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *realmResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state realmResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	realm, err := r.client.GetRealmWithContext(ctx, state.ID.ValueString())
+	if ftc_client.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading realm",
+			"Could not read realm ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	state = realmResourceModel{
+		ID:   types.StringValue(realm.ID),
+		Name: types.StringValue(realm.Name),
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *realmResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan realmResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var realm *ftc_client.Realm
+	var err error
+	obj := formatRealmObj(plan)
+
+	// if resource was deleted upstream, recreate it.
+	if plan.ID.ValueString() == "" {
+		realm, err = r.client.CreateRealmWithContext(ctx, obj)
+	} else {
+		realm, err = r.client.UpdateRealmWithContext(ctx, plan.ID.ValueString(), obj)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating realm",
+			"Could not update realm, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan = realmResourceModel{
+		ID:   types.StringValue(realm.ID),
+		Name: types.StringValue(realm.Name),
+	}
+
+	diags = resp.State.Set(ctx, plan)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *realmResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state realmResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete existing realm, a 404 means it is already gone
+	if state.ID.ValueString() != "" {
+		err := r.client.DeleteRealmWithContext(ctx, state.ID.ValueString())
+		if err != nil && !ftc_client.IsNotFound(err) {
+			resp.Diagnostics.AddError(
+				"Error Deleting realm",
+				"Could not delete realm, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
+func (r *realmResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to id attribute
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,140 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+func testAccRealmConfig(name string) string {
+	return fmt.Sprintf(`
+resource "fortitokencloud_realm" "test" {
+  name = %q
+}
+`, name)
+}
+
+// testAccCheckRealmExists checks the realm in state exists on the server with the given name.
+func testAccCheckRealmExists(client *ftc_client.Client, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["fortitokencloud_realm.test"]
+		if !ok {
+			return fmt.Errorf("fortitokencloud_realm.test not found in state")
+		}
+		realm, err := client.GetRealmWithContext(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if realm.Name != name {
+			return fmt.Errorf("realm name on server is %q, want %q", realm.Name, name)
+		}
+		return nil
+	}
+}
+
+// testAccDeleteRealm deletes the realm in state behind Terraform's back.
+func testAccDeleteRealm(client *ftc_client.Client) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		return client.DeleteRealmWithContext(context.Background(), s.RootModule().Resources["fortitokencloud_realm.test"].Primary.ID)
+	}
+}
+
+func testAccCheckRealmDestroy(client *ftc_client.Client) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "fortitokencloud_realm" {
+				continue
+			}
+			_, err := client.GetRealmWithContext(context.Background(), rs.Primary.ID)
+			if err == nil {
+				return fmt.Errorf("realm %s still exists", rs.Primary.ID)
+			}
+			if !ftc_client.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccRealmResource(t *testing.T) {
+	srv, client := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRealmDestroy(client),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: srv.ProviderConfig() + testAccRealmConfig("engineering"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_realm.test", "name", "engineering"),
+					resource.TestCheckResourceAttrSet("fortitokencloud_realm.test", "id"),
+					testAccCheckRealmExists(client, "engineering"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "fortitokencloud_realm.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: srv.ProviderConfig() + testAccRealmConfig("marketing"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_realm.test", "name", "marketing"),
+					testAccCheckRealmExists(client, "marketing"),
+				),
+			},
+			// Deleted outside of Terraform, planned for creation again
+			{
+				Config:             srv.ProviderConfig() + testAccRealmConfig("marketing"),
+				Check:              testAccDeleteRealm(client),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: srv.ProviderConfig() + testAccRealmConfig("marketing"),
+				Check:  testAccCheckRealmExists(client, "marketing"),
+			},
+		},
+	})
+}
+
+func TestAccRealmResourceConflict(t *testing.T) {
+	srv, client := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRealmDestroy(client),
+		Steps: []resource.TestStep{
+			{
+				Config:      srv.ProviderConfig() + testAccRealmConfig("default"),
+				ExpectError: regexp.MustCompile(`(?s)Could not create realm.*status: 409`),
+			},
+		},
+	})
+}
+
+func TestAccRealmResourceImportNotFound(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        srv.ProviderConfig() + testAccRealmConfig("engineering"),
+				ResourceName:  "fortitokencloud_realm.test",
+				ImportState:   true,
+				ImportStateId: "missing",
+				ExpectError:   regexp.MustCompile(`Cannot import non-existent remote object`),
+			},
+		},
+	})
+}
@@ -0,0 +1,528 @@
+package fortitokencloud
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &signingCertificateResource{}
+	_ resource.ResourceWithConfigure      = &signingCertificateResource{}
+	_ resource.ResourceWithImportState    = &signingCertificateResource{}
+	_ resource.ResourceWithValidateConfig = &signingCertificateResource{}
+	_ resource.ResourceWithModifyPlan     = &signingCertificateResource{}
+)
+
+// NewSigningCertificateResource is a helper function to simplify the provider implementation.
+func NewSigningCertificateResource() resource.Resource {
+	return &signingCertificateResource{}
+}
+
+// signingCertificateResource is the resource implementation.
+type signingCertificateResource struct {
+	client                *ftc_client.Client
+	certExpiryWarningDays int64
+}
+
+func formatSigningCertObj(plan signingCertificateResourceModel, create bool) *map[string]interface{} {
+	obj := make(map[string]interface{})
+	obj["name"] = plan.Name.ValueString()
+	if !create {
+		return &obj
+	}
+	obj["realm_id"] = plan.RealmID.ValueString()
+	if plan.CertificatePEM.ValueString() != "" {
+		obj["certificate"] = plan.CertificatePEM.ValueString()
+		if !plan.PrivateKeyPEMWO.IsNull() {
+			obj["private_key"] = plan.PrivateKeyPEMWO.ValueString()
+		} else {
+			obj["private_key"] = plan.PrivateKeyPEM.ValueString()
+		}
+	} else {
+		obj["common_name"] = plan.CommonName.ValueString()
+		if !plan.ValidityDays.IsNull() {
+			obj["validity_days"] = plan.ValidityDays.ValueInt64()
+		}
+	}
+	return &obj
+}
+
+// certificateAttributes returns the attributes derived from a PEM certificate by name, all
+// empty when it cannot be parsed.
+func certificateAttributes(data string) map[string]string {
+	attributes := map[string]string{
+		"subject": "", "issuer": "", "serial_number": "", "not_before": "", "not_after": "",
+		"fingerprint_sha1": "", "fingerprint_sha256": "",
+	}
+	cert, err := parseCertificatePEM(data)
+	if err != nil {
+		return attributes
+	}
+	attributes["subject"] = cert.Subject.String()
+	attributes["issuer"] = cert.Issuer.String()
+	attributes["serial_number"] = formatSerial(cert)
+	attributes["not_before"] = formatNotBefore(cert)
+	attributes["not_after"] = formatExpiry(cert)
+	attributes["fingerprint_sha1"] = fingerprintSHA1(cert)
+	attributes["fingerprint_sha256"] = fingerprintSHA256(cert)
+	return attributes
+}
+
+// sameCertificate reports whether two PEM strings hold the same certificate, whatever their
+// line endings or surrounding text.
+func sameCertificate(a, b string) bool {
+	cert_a, err := parseCertificatePEM(a)
+	if err != nil {
+		return false
+	}
+	cert_b, err := parseCertificatePEM(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(cert_a.Raw, cert_b.Raw)
+}
+
+// replaceIfSet only replaces the certificate when the prior value was known, so attributes FTC
+// does not return can be added to the configuration after an import.
+func replaceIfSet() stringplanmodifier.RequiresReplaceIfFunc {
+	return func(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+		resp.RequiresReplace = !req.StateValue.IsNull()
+	}
+}
+
+// Metadata returns the resource type name.
+func (r *signingCertificateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_signing_certificate"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *signingCertificateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.client
+	r.certExpiryWarningDays = data.certExpiryWarningDays
+}
+
+// Schema defines the schema for the resource.
+func (r *signingCertificateResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	computed := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{
+			Description: description,
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "IdP signing certificate of a realm, referenced by signing_cert_id on applications. Uploaded from PEM or generated by FTC.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"realm_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"certificate_pem": schema.StringAttribute{
+				Description: "PEM encoded certificate to upload. Conflicts with common_name, set when FTC generates the certificate.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"private_key_pem": schema.StringAttribute{
+				Description: "PEM encoded private key of certificate_pem. It is only sent on upload and kept in state.",
+				Optional:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(replaceIfSet(), "Changing the private key uploads a new certificate.", "Changing the private key uploads a new certificate."),
+				},
+			},
+			"private_key_pem_wo": schema.StringAttribute{
+				Description: "Write-only alternative to private_key_pem that is never stored in state. Requires Terraform 1.11 or later.",
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+			},
+			"common_name": schema.StringAttribute{
+				Description: "Common name of a certificate generated by FTC. Conflicts with certificate_pem.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(replaceIfSet(), "Changing the common name generates a new certificate.", "Changing the common name generates a new certificate."),
+				},
+			},
+			"validity_days": schema.Int64Attribute{
+				Description: "Validity of a generated certificate in days, 365 when not set.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplaceIf(func(_ context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {
+						resp.RequiresReplace = !req.StateValue.IsNull()
+					}, "Changing the validity generates a new certificate.", "Changing the validity generates a new certificate."),
+				},
+			},
+			"subject":            computed("Subject distinguished name of the certificate."),
+			"issuer":             computed("Issuer distinguished name of the certificate."),
+			"serial_number":      computed("Serial number as colon separated hex."),
+			"not_before":         computed("Start of the certificate validity in RFC 3339 format."),
+			"not_after":          computed("Expiry of the certificate in RFC 3339 format."),
+			"fingerprint_sha1":   computed("SHA-1 fingerprint of the certificate."),
+			"fingerprint_sha256": computed("SHA-256 fingerprint of the certificate."),
+		},
+	}
+}
+
+// signingCertificateResourceModel maps the resource schema data.
+type signingCertificateResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	RealmID           types.String `tfsdk:"realm_id"`
+	CertificatePEM    types.String `tfsdk:"certificate_pem"`
+	PrivateKeyPEM     types.String `tfsdk:"private_key_pem"`
+	PrivateKeyPEMWO   types.String `tfsdk:"private_key_pem_wo"`
+	CommonName        types.String `tfsdk:"common_name"`
+	ValidityDays      types.Int64  `tfsdk:"validity_days"`
+	Subject           types.String `tfsdk:"subject"`
+	Issuer            types.String `tfsdk:"issuer"`
+	SerialNumber      types.String `tfsdk:"serial_number"`
+	NotBefore         types.String `tfsdk:"not_before"`
+	NotAfter          types.String `tfsdk:"not_after"`
+	FingerprintSHA1   types.String `tfsdk:"fingerprint_sha1"`
+	FingerprintSHA256 types.String `tfsdk:"fingerprint_sha256"`
+}
+
+// signingCertificateResourceModelFrom builds the resource model from a certificate returned by
+// FTC. The private key and generation settings FTC does not return are carried over from prior.
+func signingCertificateResourceModelFrom(cert *ftc_client.SigningCertificate, prior signingCertificateResourceModel) signingCertificateResourceModel {
+	attributes := certificateAttributes(cert.Certificate)
+	model := signingCertificateResourceModel{
+		ID:                types.StringValue(cert.ID),
+		Name:              types.StringValue(cert.Name),
+		RealmID:           types.StringValue(cert.RealmID),
+		CertificatePEM:    types.StringValue(cert.Certificate),
+		PrivateKeyPEM:     prior.PrivateKeyPEM,
+		PrivateKeyPEMWO:   types.StringNull(),
+		CommonName:        prior.CommonName,
+		ValidityDays:      prior.ValidityDays,
+		Subject:           types.StringValue(attributes["subject"]),
+		Issuer:            types.StringValue(attributes["issuer"]),
+		SerialNumber:      types.StringValue(attributes["serial_number"]),
+		NotBefore:         types.StringValue(attributes["not_before"]),
+		NotAfter:          types.StringValue(attributes["not_after"]),
+		FingerprintSHA1:   types.StringValue(attributes["fingerprint_sha1"]),
+		FingerprintSHA256: types.StringValue(attributes["fingerprint_sha256"]),
+	}
+	// keep the uploaded text when FTC returns the same certificate formatted differently
+	if prior.CertificatePEM.ValueString() != "" && sameCertificate(prior.CertificatePEM.ValueString(), cert.Certificate) {
+		model.CertificatePEM = prior.CertificatePEM
+	}
+	return model
+}
+
+// ValidateConfig checks that the certificate is either uploaded with its key or generated, and
+// that an uploaded key belongs to the certificate.
+func (r *signingCertificateResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config signingCertificateResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upload := !config.CertificatePEM.IsNull()
+	generate := !config.CommonName.IsNull()
+	switch {
+	case upload && generate:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("common_name"),
+			"Conflicting certificate sources",
+			"Only one of certificate_pem and common_name can be set.",
+		)
+		return
+	case !upload && !generate:
+		resp.Diagnostics.AddError(
+			"Missing certificate source",
+			"Set certificate_pem with private_key_pem to upload a certificate, or common_name to have FTC generate one.",
+		)
+		return
+	}
+
+	if !config.PrivateKeyPEM.IsNull() && !config.PrivateKeyPEMWO.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("private_key_pem_wo"),
+			"Conflicting private keys",
+			"Only one of private_key_pem and private_key_pem_wo can be set.",
+		)
+	}
+	if generate {
+		for _, attribute := range []string{"private_key_pem", "private_key_pem_wo"} {
+			var value types.String
+			resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(attribute), &value)...)
+			if !value.IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(attribute),
+					"Invalid attribute combination",
+					attribute+" only applies to an uploaded certificate_pem.",
+				)
+			}
+		}
+		days := config.ValidityDays
+		if !days.IsNull() && !days.IsUnknown() && (days.ValueInt64() < 1 || days.ValueInt64() > 3650) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("validity_days"),
+				"Invalid validity",
+				fmt.Sprintf("validity_days must be between 1 and 3650, got %d.", days.ValueInt64()),
+			)
+		}
+		return
+	}
+
+	if !config.ValidityDays.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("validity_days"),
+			"Invalid attribute combination",
+			"validity_days only applies to a certificate generated from common_name.",
+		)
+	}
+
+	key := config.PrivateKeyPEM
+	if !config.PrivateKeyPEMWO.IsNull() {
+		key = config.PrivateKeyPEMWO
+	}
+	if key.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("private_key_pem"),
+			"Missing required attribute",
+			"private_key_pem or private_key_pem_wo is required with certificate_pem.",
+		)
+		return
+	}
+	if config.CertificatePEM.IsUnknown() || key.IsUnknown() {
+		return
+	}
+
+	cert, err := parseCertificatePEM(config.CertificatePEM.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("certificate_pem"),
+			"Invalid certificate",
+			"Could not parse certificate_pem as a PEM encoded X.509 certificate: "+err.Error(),
+		)
+		return
+	}
+	signer, err := parsePrivateKeyPEM(key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("private_key_pem"),
+			"Invalid private key",
+			"Could not parse the private key: "+err.Error(),
+		)
+		return
+	}
+	if !keyMatchesCertificate(cert, signer) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("private_key_pem"),
+			"Private key does not match certificate",
+			"The private key does not belong to certificate_pem.",
+		)
+	}
+}
+
+// ModifyPlan derives subject, serial, validity and fingerprints from an uploaded certificate so
+// they show in the plan, and flags a certificate that has expired or expires soon. Generated
+// certificates only have them after apply.
+func (r *signingCertificateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to derive on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	r.planCertificateExpiry(ctx, req, resp)
+
+	var certificate_pem types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("certificate_pem"), &certificate_pem)...)
+	if resp.Diagnostics.HasError() || certificate_pem.IsNull() || certificate_pem.IsUnknown() {
+		return
+	}
+
+	for attribute, value := range certificateAttributes(certificate_pem.ValueString()) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attribute), value)...)
+	}
+}
+
+// planCertificateExpiry checks the planned certificate, uploaded or kept from state. Only an
+// uploaded certificate that has expired fails the plan, a generated one kept from state must
+// stay plannable so common_name, validity_days or -replace can rotate it.
+func (r *signingCertificateResource) planCertificateExpiry(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var planned, configured types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("certificate_pem"), &planned)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("certificate_pem"), &configured)...)
+	if resp.Diagnostics.HasError() || planned.IsUnknown() {
+		return
+	}
+	resp.Diagnostics.Append(checkCertificateExpiry(planned.ValueString(), path.Root("certificate_pem"), r.certExpiryWarningDays, !configured.IsNull())...)
+}
+
+// Create a new resource.
+func (r *signingCertificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan signingCertificateResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("private_key_pem_wo"), &plan.PrivateKeyPEMWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	obj := formatSigningCertObj(plan, true)
+
+	// Upload or generate the certificate
+	cert, err := r.client.CreateSigningCertificateWithContext(ctx, obj)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating signing certificate",
+			"Could not create signing certificate, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan = signingCertificateResourceModelFrom(cert, plan)
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information.
+func (r *signingCertificateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state signingCertificateResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cert, err := r.client.GetSigningCertificateWithContext(ctx, state.ID.ValueString())
+	if ftc_client.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading signing certificate",
+			"Could not read signing certificate ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	state = signingCertificateResourceModelFrom(cert, state)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update renames the certificate, every other change replaces it.
+func (r *signingCertificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan
+	var plan signingCertificateResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	obj := formatSigningCertObj(plan, false)
+
+	cert, err := r.client.UpdateSigningCertificateWithContext(ctx, plan.ID.ValueString(), obj)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating signing certificate",
+			"Could not update signing certificate, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan = signingCertificateResourceModelFrom(cert, plan)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *signingCertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Retrieve values from state
+	var state signingCertificateResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete existing signing certificate, a 404 means it is already gone
+	if state.ID.ValueString() != "" {
+		err := r.client.DeleteSigningCertificateWithContext(ctx, state.ID.ValueString())
+		if err != nil && !ftc_client.IsNotFound(err) {
+			resp.Diagnostics.AddError(
+				"Error Deleting signing certificate",
+				"Could not delete signing certificate, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
+func (r *signingCertificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to id attribute
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
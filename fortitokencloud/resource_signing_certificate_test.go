@@ -0,0 +1,351 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"terraform-provider-fortitokencloud/sdk/ftctest"
+)
+
+func TestCertificateAttributes(t *testing.T) {
+	cert_pem, _ := testKeyPair(t, "idp.example.com", time.Date(2031, 5, 4, 10, 30, 0, 0, time.UTC))
+	attributes := certificateAttributes(cert_pem)
+	if attributes["subject"] != "CN=idp.example.com" || attributes["issuer"] != "CN=idp.example.com" {
+		t.Errorf("subject %q, issuer %q", attributes["subject"], attributes["issuer"])
+	}
+	if attributes["not_before"] != "2030-05-04T10:30:00Z" || attributes["not_after"] != "2031-05-04T10:30:00Z" {
+		t.Errorf("validity %s to %s", attributes["not_before"], attributes["not_after"])
+	}
+	for _, name := range []string{"serial_number", "fingerprint_sha1", "fingerprint_sha256"} {
+		if attributes[name] == "" {
+			t.Errorf("%s is empty", name)
+		}
+	}
+
+	for name, value := range certificateAttributes("not PEM") {
+		if value != "" {
+			t.Errorf("%s = %q for an invalid certificate", name, value)
+		}
+	}
+}
+
+func TestSameCertificate(t *testing.T) {
+	cert_pem, _ := testKeyPair(t, "idp.example.com", time.Now().Add(time.Hour))
+	other_pem, _ := testKeyPair(t, "idp.example.com", time.Now().Add(time.Hour))
+	if !sameCertificate(cert_pem, strings.ReplaceAll(cert_pem, "\n", "\r\n")+"\n") {
+		t.Error("the same certificate with other line endings differs")
+	}
+	if sameCertificate(cert_pem, other_pem) || sameCertificate(cert_pem, "not PEM") {
+		t.Error("different certificates are the same")
+	}
+}
+
+// testAccSigningCertificateGeneratedConfig has FTC generate a certificate for common_name.
+func testAccSigningCertificateGeneratedConfig(name, common_name string) string {
+	return fmt.Sprintf(`
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_signing_certificate" "test" {
+  name          = %q
+  realm_id      = data.fortitokencloud_realm.test.id
+  common_name   = %q
+  validity_days = 30
+}
+`, name, common_name)
+}
+
+// testAccSigningCertificateUploadConfig uploads cert_pem with its key in key_attribute.
+func testAccSigningCertificateUploadConfig(cert_pem, key_attribute, key_pem string) string {
+	return fmt.Sprintf(`
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_signing_certificate" "test" {
+  name            = "uploaded"
+  realm_id        = data.fortitokencloud_realm.test.id
+  certificate_pem = %q
+  %s = %q
+}
+`, cert_pem, key_attribute, key_pem)
+}
+
+// testAccSigningCertificateID returns the ID of fortitokencloud_signing_certificate.test in state.
+func testAccSigningCertificateID(s *terraform.State) (string, error) {
+	rs, ok := s.RootModule().Resources["fortitokencloud_signing_certificate.test"]
+	if !ok {
+		return "", fmt.Errorf("fortitokencloud_signing_certificate.test not found in state")
+	}
+	return rs.Primary.ID, nil
+}
+
+func testAccCheckSigningCertificateDestroy(srv *ftctest.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "fortitokencloud_signing_certificate" {
+				continue
+			}
+			if _, ok := srv.SigningCertificate(rs.Primary.ID); ok {
+				return fmt.Errorf("signing certificate %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccSigningCertificateResourceGenerated(t *testing.T) {
+	srv, client := testAccServer(t)
+	var cert_id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckSigningCertificateDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccSigningCertificateGeneratedConfig("generated", "idp.example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_signing_certificate.test", "subject", "CN=idp.example.com"),
+					resource.TestCheckResourceAttrSet("fortitokencloud_signing_certificate.test", "certificate_pem"),
+					resource.TestCheckResourceAttrSet("fortitokencloud_signing_certificate.test", "fingerprint_sha256"),
+					resource.TestCheckResourceAttrSet("fortitokencloud_signing_certificate.test", "not_after"),
+					func(s *terraform.State) error {
+						id, err := testAccSigningCertificateID(s)
+						cert_id = id
+						return err
+					},
+				),
+			},
+			// common_name and validity_days are not returned by FTC
+			{
+				ResourceName:            "fortitokencloud_signing_certificate.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"common_name", "validity_days"},
+			},
+			// a rename keeps the certificate
+			{
+				Config: srv.ProviderConfig() + testAccSigningCertificateGeneratedConfig("renamed", "idp.example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_signing_certificate.test", "name", "renamed"),
+					resource.TestCheckResourceAttrWith("fortitokencloud_signing_certificate.test", "id", func(id string) error {
+						if id != cert_id {
+							return fmt.Errorf("rename replaced certificate %s with %s", cert_id, id)
+						}
+						return nil
+					}),
+				),
+			},
+			// a new common name generates a new certificate
+			{
+				Config: srv.ProviderConfig() + testAccSigningCertificateGeneratedConfig("renamed", "sso.example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_signing_certificate.test", "subject", "CN=sso.example.com"),
+					resource.TestCheckResourceAttrWith("fortitokencloud_signing_certificate.test", "id", func(id string) error {
+						if id == cert_id {
+							return fmt.Errorf("certificate %s was not replaced", id)
+						}
+						cert_id = id
+						return nil
+					}),
+				),
+			},
+			// a certificate deleted outside Terraform is generated again
+			{
+				PreConfig: func() {
+					if err := client.DeleteSigningCertificateWithContext(context.Background(), cert_id); err != nil {
+						t.Fatalf("deleting signing certificate: %s", err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccSigningCertificateGeneratedConfig("renamed", "sso.example.com"),
+				Check: resource.TestCheckResourceAttrWith("fortitokencloud_signing_certificate.test", "id", func(id string) error {
+					if id == cert_id {
+						return fmt.Errorf("certificate %s was not generated again", id)
+					}
+					return nil
+				}),
+			},
+			{
+				ResourceName:  "fortitokencloud_signing_certificate.test",
+				ImportState:   true,
+				ImportStateId: "missing",
+				ExpectError:   regexp.MustCompile(`Cannot import non-existent remote object`),
+			},
+		},
+	})
+}
+
+func TestAccSigningCertificateResourceUpload(t *testing.T) {
+	srv, _ := testAccServer(t)
+	cert_pem, key_pem := testKeyPair(t, "idp.example.com", time.Now().Add(24*time.Hour))
+	attributes := certificateAttributes(cert_pem)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckSigningCertificateDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccSigningCertificateUploadConfig(cert_pem, "private_key_pem", key_pem),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_signing_certificate.test", "certificate_pem", cert_pem),
+					resource.TestCheckResourceAttr("fortitokencloud_signing_certificate.test", "fingerprint_sha256", attributes["fingerprint_sha256"]),
+					resource.TestCheckResourceAttr("fortitokencloud_signing_certificate.test", "serial_number", attributes["serial_number"]),
+					resource.TestCheckResourceAttr("fortitokencloud_signing_certificate.test", "not_after", attributes["not_after"]),
+				),
+			},
+			{
+				ResourceName:            "fortitokencloud_signing_certificate.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"private_key_pem"},
+			},
+		},
+	})
+}
+
+func TestAccSigningCertificateResourceWriteOnlyKey(t *testing.T) {
+	srv, _ := testAccServer(t)
+	cert_pem, key_pem := testKeyPair(t, "idp.example.com", time.Now().Add(24*time.Hour))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		CheckDestroy: testAccCheckSigningCertificateDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccSigningCertificateUploadConfig(cert_pem, "private_key_pem_wo", key_pem),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_signing_certificate.test", "certificate_pem", cert_pem),
+					resource.TestCheckNoResourceAttr("fortitokencloud_signing_certificate.test", "private_key_pem"),
+					resource.TestCheckNoResourceAttr("fortitokencloud_signing_certificate.test", "private_key_pem_wo"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSigningCertificateResourceValidation(t *testing.T) {
+	srv, _ := testAccServer(t)
+	cert_pem, key_pem := testKeyPair(t, "idp.example.com", time.Now().Add(24*time.Hour))
+	_, other_key_pem := testKeyPair(t, "other.example.com", time.Now().Add(24*time.Hour))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      srv.ProviderConfig() + testAccSigningCertificateUploadConfig(cert_pem, "private_key_pem", other_key_pem),
+				ExpectError: regexp.MustCompile(`Private key does not match certificate`),
+			},
+			{
+				Config:      srv.ProviderConfig() + testAccSigningCertificateUploadConfig("not PEM", "private_key_pem", key_pem),
+				ExpectError: regexp.MustCompile(`Invalid certificate`),
+			},
+			{
+				Config:      srv.ProviderConfig() + testAccSigningCertificateUploadConfig(cert_pem, "common_name", "idp.example.com"),
+				ExpectError: regexp.MustCompile(`Only one of certificate_pem and common_name can be set`),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+resource "fortitokencloud_signing_certificate" "test" {
+  name     = "empty"
+  realm_id = "realm"
+}
+`,
+				ExpectError: regexp.MustCompile(`Missing certificate source`),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+resource "fortitokencloud_signing_certificate" "test" {
+  name            = "no key"
+  realm_id        = "realm"
+  certificate_pem = "cert"
+}
+`,
+				ExpectError: regexp.MustCompile(`private_key_pem or private_key_pem_wo is required\s+with\s+certificate_pem`),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+resource "fortitokencloud_signing_certificate" "test" {
+  name          = "generated"
+  realm_id      = "realm"
+  common_name   = "idp.example.com"
+  validity_days = 0
+}
+`,
+				ExpectError: regexp.MustCompile(`validity_days must be between 1 and 3650, got 0`),
+			},
+		},
+	})
+}
+
+func TestAccSigningCertificateResourceExpiry(t *testing.T) {
+	srv, _ := testAccServer(t)
+	var cert_id string
+	expired_pem, expired_key_pem := testKeyPair(t, "idp.example.com", time.Now().Add(-time.Hour))
+	expiring_pem, expiring_key_pem := testKeyPair(t, "idp.example.com", time.Now().Add(10*24*time.Hour))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckSigningCertificateDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config:      srv.ProviderConfig() + testAccSigningCertificateUploadConfig(expired_pem, "private_key_pem", expired_key_pem),
+				ExpectError: regexp.MustCompile(`Certificate expired`),
+			},
+			// a certificate expiring soon only warns
+			{
+				Config: srv.ProviderConfig() + testAccSigningCertificateUploadConfig(expiring_pem, "private_key_pem", expiring_key_pem),
+				Check:  resource.TestCheckResourceAttr("fortitokencloud_signing_certificate.test", "certificate_pem", expiring_pem),
+			},
+			{
+				Config: srv.ProviderConfig() + testAccSigningCertificateGeneratedConfig("generated", "idp.example.com"),
+				Check: func(s *terraform.State) (err error) {
+					cert_id, err = testAccSigningCertificateID(s)
+					return err
+				},
+			},
+			// a generated certificate that expired in FTC only warns, so it can still be rotated
+			{
+				PreConfig: func() {
+					if err := srv.ExpireSigningCertificate(cert_id); err != nil {
+						t.Fatalf("expiring signing certificate: %s", err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccSigningCertificateGeneratedConfig("generated", "idp.example.com"),
+				Check: func(s *terraform.State) error {
+					if id, _ := testAccSigningCertificateID(s); id != cert_id {
+						return fmt.Errorf("expired signing certificate %s was replaced by %s without a change", cert_id, id)
+					}
+					return nil
+				},
+			},
+			{
+				Config: srv.ProviderConfig() + testAccSigningCertificateGeneratedConfig("generated", "sso.example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_signing_certificate.test", "subject", "CN=sso.example.com"),
+					func(s *terraform.State) error {
+						id, err := testAccSigningCertificateID(s)
+						if err != nil {
+							return err
+						}
+						if id == cert_id {
+							return fmt.Errorf("expired signing certificate %s was not replaced", id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
@@ -2,28 +2,34 @@ package fortitokencloud
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net/http"
+	"net/url"
 	"reflect"
+	"slices"
 	"strings"
 	ftc_client "terraform-provider-fortitokencloud/sdk"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_            resource.Resource                = &userSourceResource{}
-	_            resource.ResourceWithConfigure   = &userSourceResource{}
-	_            resource.ResourceWithImportState = &userSourceResource{}
-	type_int_map                                  = map[int64]string{
+	_            resource.Resource                   = &userSourceResource{}
+	_            resource.ResourceWithConfigure      = &userSourceResource{}
+	_            resource.ResourceWithImportState    = &userSourceResource{}
+	_            resource.ResourceWithValidateConfig = &userSourceResource{}
+	_            resource.ResourceWithModifyPlan     = &userSourceResource{}
+	_            resource.ResourceWithUpgradeState   = &userSourceResource{}
+	type_int_map                                     = map[int64]string{
 		1: "saml",
 		2: "oidc",
 	}
@@ -31,6 +37,13 @@ var (
 		"saml": 1,
 		"oidc": 2,
 	}
+	// domain_mode values, a non-authoritative user source leaves domains it does not list alone
+	us_domain_modes = []string{"authoritative", "non_authoritative"}
+	// attributes of each settings block holding absolute http or https URLs
+	us_url_attributes = map[string][]string{
+		"saml": {"login_url", "logout_url", "idp_metadata_url"},
+		"oidc": {"auth_uri", "token_uri", "userinfo_uri", "logout_uri", "issuer", "discovery_url"},
+	}
 )
 
 // NewUserSourceResource is a helper function to simplify the provider implementation.
@@ -40,7 +53,8 @@ func NewUserSourceResource() resource.Resource {
 
 // UserSourceResource is the resource implementation.
 type userSourceResource struct {
-	client *ftc_client.Client
+	client                *ftc_client.Client
+	certExpiryWarningDays int64
 }
 
 func formatUsObj(plan userSourceResourceModel, create bool) (*map[string]interface{}, map[string][]string) {
@@ -61,72 +75,73 @@ func formatUsObj(plan userSourceResourceModel, create bool) (*map[string]interfa
 	} else {
 		obj["login_hint"] = plan.LoginHint.ValueString()
 	}
-	if plan.AttrMapping.ValueString() == "" {
-		obj["attr_mapping"] = map[string]interface{}{}
-	} else {
-		var attr_mapping map[string]interface{}
-		err := json.Unmarshal([]byte(plan.AttrMapping.ValueString()), &attr_mapping)
-		if err != nil {
-			return nil, domain_list
-		}
-		obj["attr_mapping"] = attr_mapping
-	}
-	if strings.ToLower(plan.Type.ValueString()) == "saml" {
+	obj["attr_mapping"] = attrMappingPayload(plan.AttrMapping)
+	if saml := plan.Saml; saml != nil {
 		saml_obj := map[string]interface{}{}
-		if plan.EntityID.ValueString() == "" {
+		if saml.EntityID.ValueString() == "" {
 			saml_obj["entity_id"] = nil
 		} else {
-			saml_obj["entity_id"] = plan.EntityID.ValueString()
+			saml_obj["entity_id"] = saml.EntityID.ValueString()
 		}
-		if plan.LogoutUrl.ValueString() == "" {
+		if saml.LoginUrl.ValueString() == "" {
 			saml_obj["login_url"] = nil
 		} else {
-			saml_obj["login_url"] = plan.LoginUrl.ValueString()
+			saml_obj["login_url"] = saml.LoginUrl.ValueString()
 		}
-		if plan.LogoutUrl.ValueString() == "" {
+		if saml.LogoutUrl.ValueString() == "" {
 			saml_obj["logout_url"] = nil
 		} else {
-			saml_obj["logout_url"] = plan.LogoutUrl.ValueString()
+			saml_obj["logout_url"] = saml.LogoutUrl.ValueString()
 		}
-		saml_obj["post_binding"] = plan.PostBinding.ValueBool()
-		saml_obj["include_subject"] = plan.IncludeSubject.ValueBool()
+		if saml.SigningCert.ValueString() == "" {
+			saml_obj["signing_cert"] = nil
+		} else {
+			saml_obj["signing_cert"] = saml.SigningCert.ValueString()
+		}
+		saml_obj["post_binding"] = saml.PostBinding.ValueBool()
+		saml_obj["include_subject"] = saml.IncludeSubject.ValueBool()
 		obj["saml_params"] = saml_obj
-	} else {
+	} else if oidc := plan.Oidc; oidc != nil {
 		odic_obj := map[string]interface{}{}
-		if plan.AuthUri.ValueString() == "" {
+		if oidc.AuthUri.ValueString() == "" {
 			odic_obj["auth_uri"] = nil
 		} else {
-			odic_obj["auth_uri"] = plan.AuthUri.ValueString()
+			odic_obj["auth_uri"] = oidc.AuthUri.ValueString()
 		}
-		if plan.TokenUri.ValueString() == "" {
+		if oidc.TokenUri.ValueString() == "" {
 			odic_obj["token_uri"] = nil
 		} else {
-			odic_obj["token_uri"] = plan.TokenUri.ValueString()
+			odic_obj["token_uri"] = oidc.TokenUri.ValueString()
 		}
-		if plan.UserInfoUri.ValueString() == "" {
+		if oidc.UserInfoUri.ValueString() == "" {
 			odic_obj["userinfo_uri"] = nil
 		} else {
-			odic_obj["userinfo_uri"] = plan.UserInfoUri.ValueString()
+			odic_obj["userinfo_uri"] = oidc.UserInfoUri.ValueString()
 		}
-		if plan.LogoutUri.ValueString() == "" {
+		if oidc.LogoutUri.ValueString() == "" {
 			odic_obj["logout_uri"] = nil
 		} else {
-			odic_obj["logout_uri"] = plan.LogoutUri.ValueString()
+			odic_obj["logout_uri"] = oidc.LogoutUri.ValueString()
 		}
-		if plan.Issuer.ValueString() == "" {
+		if oidc.Issuer.ValueString() == "" {
 			odic_obj["issuer"] = nil
 		} else {
-			odic_obj["issuer"] = plan.Issuer.ValueString()
+			odic_obj["issuer"] = oidc.Issuer.ValueString()
 		}
-		if plan.ClientID.ValueString() == "" {
+		if oidc.ClientID.ValueString() == "" {
 			odic_obj["client_id"] = nil
 		} else {
-			odic_obj["client_id"] = plan.ClientID.ValueString()
+			odic_obj["client_id"] = oidc.ClientID.ValueString()
 		}
-		if plan.ClientSecret.ValueString() == "" {
+		switch {
+		case !oidc.ClientSecretWO.IsNull():
+			odic_obj["client_secret"] = oidc.ClientSecretWO.ValueString()
+		case !oidc.ClientSecretVersion.IsNull():
+			// the write-only secret is unchanged, leave it out so FTC keeps it
+		case oidc.ClientSecret.ValueString() == "":
 			odic_obj["client_secret"] = nil
-		} else {
-			odic_obj["client_secret"] = plan.ClientSecret.ValueString()
+		default:
+			odic_obj["client_secret"] = oidc.ClientSecret.ValueString()
 		}
 		obj["oidc_params"] = odic_obj
 	}
@@ -150,23 +165,25 @@ func (r *userSourceResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*ftc_client.Client)
+	data, ok := req.ProviderData.(*resourceData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *ftc.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.certExpiryWarningDays = data.certExpiryWarningDays
 }
 
 // Schema defines the schema for the resource.
 func (r *userSourceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 2,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed: true,
@@ -178,57 +195,8 @@ func (r *userSourceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Required: true,
 			},
 			"type": schema.StringAttribute{
-				Required: true,
-			},
-			"entity_id": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
-			},
-			"login_url": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
-			},
-			"logout_url": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
-			},
-			"auth_uri": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
-			},
-			"token_uri": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
-			},
-			"userinfo_uri": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
-			},
-			"logout_uri": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
-			},
-			"issuer": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
-			},
-			"client_id": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
-			},
-			"client_secret": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
-				Default:  stringdefault.StaticString(""),
+				Description: "User source type, \"saml\" or \"oidc\". The block of the same name holds its settings.",
+				Required:    true,
 			},
 			"realm_id": schema.StringAttribute{
 				Required: true,
@@ -236,14 +204,6 @@ func (r *userSourceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"prefix": schema.StringAttribute{
 				Computed: true,
 			},
-			"post_binding": schema.BoolAttribute{
-				Optional: true,
-				Computed: true,
-			},
-			"include_subject": schema.BoolAttribute{
-				Optional: true,
-				Computed: true,
-			},
 			"username_assertion": schema.StringAttribute{
 				Optional: true,
 				Computed: true,
@@ -254,9 +214,11 @@ func (r *userSourceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Computed: true,
 				Default:  stringdefault.StaticString(""),
 			},
-			"attr_mapping": schema.StringAttribute{
-				Optional: true,
-				Computed: true,
+			"attr_mapping": schema.MapAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
 			},
 			"domain_ids": schema.SetAttribute{
 				Optional:    true,
@@ -264,6 +226,12 @@ func (r *userSourceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				ElementType: types.StringType,
 				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
 			},
+			"domain_mode": schema.StringAttribute{
+				Description: "How domain_ids is applied. \"authoritative\" (default) detaches every domain not listed, \"non_authoritative\" only attaches and detaches the listed domains so fortitokencloud_usersource_domain_attachment can manage the others.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("authoritative"),
+			},
 			"proxy_entity_id": schema.StringAttribute{
 				Computed: true,
 			},
@@ -286,39 +254,639 @@ func (r *userSourceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Computed: true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"saml": schema.SingleNestedBlock{
+				Description: "SAML IdP settings, required when type = \"saml\". Conflicts with oidc.",
+				Attributes: map[string]schema.Attribute{
+					"entity_id": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"login_url": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"logout_url": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"signing_cert": schema.StringAttribute{
+						Description: "PEM encoded IdP signing certificate.",
+						Optional:    true,
+						Computed:    true,
+					},
+					"signing_cert_fingerprint": schema.StringAttribute{
+						Description: "SHA-256 fingerprint of signing_cert.",
+						Computed:    true,
+					},
+					"signing_cert_expiry": schema.StringAttribute{
+						Description: "Expiry of signing_cert in RFC 3339 format.",
+						Computed:    true,
+					},
+					"post_binding": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"include_subject": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"idp_metadata_xml": schema.StringAttribute{
+						Description: "SAML IdP metadata document used to fill entity_id, login_url, logout_url, post_binding and signing_cert.",
+						Optional:    true,
+					},
+					"idp_metadata_url": schema.StringAttribute{
+						Description: "URL of the SAML IdP metadata document, fetched at plan time. Conflicts with idp_metadata_xml.",
+						Optional:    true,
+					},
+				},
+			},
+			"oidc": schema.SingleNestedBlock{
+				Description: "OIDC provider settings, required when type = \"oidc\". Conflicts with saml.",
+				Attributes: map[string]schema.Attribute{
+					"auth_uri": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"token_uri": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"userinfo_uri": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"logout_uri": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"issuer": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"discovery_url": schema.StringAttribute{
+						Description: "OIDC discovery URL or issuer, read at plan time to fill auth_uri, token_uri, userinfo_uri, logout_uri and issuer.",
+						Optional:    true,
+					},
+					"client_id": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString(""),
+					},
+					"client_secret": schema.StringAttribute{
+						Description: "OIDC client secret, kept in state. Use client_secret_wo to keep it out of state.",
+						Optional:    true,
+						Computed:    true,
+						Sensitive:   true,
+						Default:     stringdefault.StaticString(""),
+					},
+					"client_secret_wo": schema.StringAttribute{
+						Description: "Write-only OIDC client secret, never stored in state. Sent on create and whenever client_secret_version changes. Conflicts with client_secret.",
+						Optional:    true,
+						Sensitive:   true,
+						WriteOnly:   true,
+					},
+					"client_secret_version": schema.Int64Attribute{
+						Description: "Version of client_secret_wo, change it to send a new secret.",
+						Optional:    true,
+					},
+				},
+			},
+		},
 	}
 }
 
 // userSourceResourceModel maps the resource schema data.
 type userSourceResourceModel struct {
-	ID                         types.String   `tfsdk:"id"`
-	Name                       types.String   `tfsdk:"name"`
-	Type                       types.String   `tfsdk:"type"`
-	EntityID                   types.String   `tfsdk:"entity_id"`
-	LoginUrl                   types.String   `tfsdk:"login_url"`
-	LogoutUrl                  types.String   `tfsdk:"logout_url"`
-	AuthUri                    types.String   `tfsdk:"auth_uri"`
-	TokenUri                   types.String   `tfsdk:"token_uri"`
-	UserInfoUri                types.String   `tfsdk:"userinfo_uri"`
-	LogoutUri                  types.String   `tfsdk:"logout_uri"`
-	Issuer                     types.String   `tfsdk:"issuer"`
-	ClientID                   types.String   `tfsdk:"client_id"`
-	ClientSecret               types.String   `tfsdk:"client_secret"`
-	RealmID                    types.String   `tfsdk:"realm_id"`
-	Prefix                     types.String   `tfsdk:"prefix"`
-	PostBinding                types.Bool     `tfsdk:"post_binding"`
-	IncludeSubject             types.Bool     `tfsdk:"include_subject"`
-	UsernameAssertion          types.String   `tfsdk:"username_assertion"`
-	LoginHint                  types.String   `tfsdk:"login_hint"`
-	Domains                    []types.String `tfsdk:"domain_ids"`
-	ProxyEntityID              types.String   `tfsdk:"proxy_entity_id"`
-	ProxyAcsUrl                types.String   `tfsdk:"proxy_acs_url"`
-	ProxySloUrl                types.String   `tfsdk:"proxy_slo_url"`
-	ProxySSoUrl                types.String   `tfsdk:"proxy_sso_url"`
-	ProxyCallbackUrl           types.String   `tfsdk:"proxy_callback_url"`
-	ProxyPostLogoutRedirectUri types.String   `tfsdk:"proxy_post_logout_redirect_uri"`
-	ProxyOidcLoginUrl          types.String   `tfsdk:"proxy_oidc_login_url"`
-	AttrMapping                types.String   `tfsdk:"attr_mapping"`
+	ID                         types.String         `tfsdk:"id"`
+	Name                       types.String         `tfsdk:"name"`
+	Type                       types.String         `tfsdk:"type"`
+	RealmID                    types.String         `tfsdk:"realm_id"`
+	Prefix                     types.String         `tfsdk:"prefix"`
+	UsernameAssertion          types.String         `tfsdk:"username_assertion"`
+	LoginHint                  types.String         `tfsdk:"login_hint"`
+	Domains                    []types.String       `tfsdk:"domain_ids"`
+	DomainMode                 types.String         `tfsdk:"domain_mode"`
+	ProxyEntityID              types.String         `tfsdk:"proxy_entity_id"`
+	ProxyAcsUrl                types.String         `tfsdk:"proxy_acs_url"`
+	ProxySloUrl                types.String         `tfsdk:"proxy_slo_url"`
+	ProxySSoUrl                types.String         `tfsdk:"proxy_sso_url"`
+	ProxyCallbackUrl           types.String         `tfsdk:"proxy_callback_url"`
+	ProxyPostLogoutRedirectUri types.String         `tfsdk:"proxy_post_logout_redirect_uri"`
+	ProxyOidcLoginUrl          types.String         `tfsdk:"proxy_oidc_login_url"`
+	AttrMapping                types.Map            `tfsdk:"attr_mapping"`
+	Saml                       *userSourceSamlModel `tfsdk:"saml"`
+	Oidc                       *userSourceOidcModel `tfsdk:"oidc"`
+}
+
+// userSourceSamlModel maps the saml block.
+type userSourceSamlModel struct {
+	EntityID               types.String `tfsdk:"entity_id"`
+	LoginUrl               types.String `tfsdk:"login_url"`
+	LogoutUrl              types.String `tfsdk:"logout_url"`
+	SigningCert            types.String `tfsdk:"signing_cert"`
+	SigningCertFingerprint types.String `tfsdk:"signing_cert_fingerprint"`
+	SigningCertExpiry      types.String `tfsdk:"signing_cert_expiry"`
+	PostBinding            types.Bool   `tfsdk:"post_binding"`
+	IncludeSubject         types.Bool   `tfsdk:"include_subject"`
+	IdPMetadataXML         types.String `tfsdk:"idp_metadata_xml"`
+	IdPMetadataURL         types.String `tfsdk:"idp_metadata_url"`
+}
+
+// userSourceOidcModel maps the oidc block.
+type userSourceOidcModel struct {
+	AuthUri             types.String `tfsdk:"auth_uri"`
+	TokenUri            types.String `tfsdk:"token_uri"`
+	UserInfoUri         types.String `tfsdk:"userinfo_uri"`
+	LogoutUri           types.String `tfsdk:"logout_uri"`
+	Issuer              types.String `tfsdk:"issuer"`
+	DiscoveryURL        types.String `tfsdk:"discovery_url"`
+	ClientID            types.String `tfsdk:"client_id"`
+	ClientSecret        types.String `tfsdk:"client_secret"`
+	ClientSecretWO      types.String `tfsdk:"client_secret_wo"`
+	ClientSecretVersion types.Int64  `tfsdk:"client_secret_version"`
+}
+
+// userSourceResourceModelFrom builds the resource model from a user source returned by FTC. The metadata,
+// discovery and client secret settings FTC does not return are carried over from prior.
+func userSourceResourceModelFrom(usersource *ftc_client.UserSource, prior userSourceResourceModel) userSourceResourceModel {
+	domain_list := make([]types.String, 0)
+	for _, domain := range usersource.Domains {
+		domain_list = append(domain_list, types.StringValue(domain.ID))
+	}
+	model := userSourceResourceModel{
+		ID:                         types.StringValue(usersource.ID),
+		Name:                       types.StringValue(usersource.Name),
+		Type:                       types.StringValue(type_int_map[int64(usersource.Type)]),
+		RealmID:                    types.StringValue(usersource.RealmID),
+		Prefix:                     types.StringValue(usersource.Prefix),
+		UsernameAssertion:          types.StringValue(usersource.UsernameAssertion),
+		LoginHint:                  types.StringValue(usersource.LoginHint),
+		Domains:                    domain_list,
+		DomainMode:                 prior.DomainMode,
+		ProxyEntityID:              types.StringValue(usersource.ProxySP.EntityID),
+		ProxyAcsUrl:                types.StringValue(usersource.ProxySP.AcsUrl),
+		ProxySloUrl:                types.StringValue(usersource.ProxySP.SloUrl),
+		ProxySSoUrl:                types.StringValue(usersource.ProxySP.SsoUrl),
+		ProxyCallbackUrl:           types.StringValue(usersource.ProxySP.CallbackUrl),
+		ProxyPostLogoutRedirectUri: types.StringValue(usersource.ProxySP.PostLogoutRedirectUrl),
+		ProxyOidcLoginUrl:          types.StringValue(usersource.ProxySP.OidcLoginUrl),
+		AttrMapping:                attrMappingValue(usersource.AttrMapping),
+	}
+
+	// keep the casing of the configured type
+	if strings.EqualFold(prior.Type.ValueString(), model.Type.ValueString()) {
+		model.Type = prior.Type
+	}
+	// imported and upgraded state has no mode yet
+	if model.DomainMode.ValueString() == "" {
+		model.DomainMode = types.StringValue("authoritative")
+	}
+	// domains attached elsewhere are not tracked in non-authoritative mode
+	if model.DomainMode.ValueString() == "non_authoritative" {
+		model.Domains = make([]types.String, 0)
+		for _, domain := range domain_list {
+			for _, prior_domain := range prior.Domains {
+				if domain.Equal(prior_domain) {
+					model.Domains = append(model.Domains, domain)
+				}
+			}
+		}
+	}
+
+	switch strings.ToLower(model.Type.ValueString()) {
+	case "saml":
+		fingerprint, expiry := certificateDetails(usersource.SigningCert)
+		model.Saml = &userSourceSamlModel{
+			EntityID:               types.StringValue(usersource.EntityID),
+			LoginUrl:               types.StringValue(usersource.LoginUrl),
+			LogoutUrl:              types.StringValue(usersource.LogoutUrl),
+			SigningCert:            types.StringValue(usersource.SigningCert),
+			SigningCertFingerprint: fingerprint,
+			SigningCertExpiry:      expiry,
+			PostBinding:            types.BoolValue(usersource.PostBinding),
+			IncludeSubject:         types.BoolValue(usersource.IncludeSubject),
+			IdPMetadataXML:         types.StringNull(),
+			IdPMetadataURL:         types.StringNull(),
+		}
+		if prior.Saml != nil {
+			model.Saml.IdPMetadataXML = prior.Saml.IdPMetadataXML
+			model.Saml.IdPMetadataURL = prior.Saml.IdPMetadataURL
+		}
+	case "oidc":
+		model.Oidc = &userSourceOidcModel{
+			AuthUri:             types.StringValue(usersource.AuthUri),
+			TokenUri:            types.StringValue(usersource.TokenUri),
+			UserInfoUri:         types.StringValue(usersource.UserInfoUri),
+			LogoutUri:           types.StringValue(usersource.LogoutUri),
+			Issuer:              types.StringValue(usersource.Issuer),
+			DiscoveryURL:        types.StringNull(),
+			ClientID:            types.StringValue(usersource.ClientID),
+			ClientSecret:        types.StringValue(usersource.ClientSecret),
+			ClientSecretWO:      types.StringNull(),
+			ClientSecretVersion: types.Int64Null(),
+		}
+		// FTC withholds the secret, keep the one Terraform sent
+		if prior.Oidc != nil {
+			model.Oidc.DiscoveryURL = prior.Oidc.DiscoveryURL
+			model.Oidc.ClientSecretVersion = prior.Oidc.ClientSecretVersion
+			if prior.Oidc.ClientSecret.ValueString() != "" || !prior.Oidc.ClientSecretVersion.IsNull() {
+				model.Oidc.ClientSecret = prior.Oidc.ClientSecret
+			}
+		}
+	}
+	return model
+}
+
+// userSourceDomainIDs returns the domains a user source should end up with. Authoritative mode
+// replaces current with wanted, non-authoritative mode adds wanted and drops only removed.
+func userSourceDomainIDs(current, wanted, removed []string, domain_mode string) []string {
+	domain_ids := make([]string, 0, len(current)+len(wanted))
+	if domain_mode == "non_authoritative" {
+		for _, domain_id := range current {
+			if !slices.Contains(removed, domain_id) && !slices.Contains(wanted, domain_id) {
+				domain_ids = append(domain_ids, domain_id)
+			}
+		}
+	}
+	return append(domain_ids, wanted...)
+}
+
+// UpgradeState migrates state written before the saml and oidc blocks, including state from
+// before attr_mapping became a map.
+func (r *userSourceResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: upgradeUserSourceV0(),
+		1: upgradeUserSourceV1(),
+	}
+}
+
+// ValidateConfig checks the type against the settings blocks, the attributes each type requires
+// and the URL attributes, so mistakes surface at plan time instead of as API errors.
+func (r *userSourceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var us_type, domain_mode types.String
+	blocks := map[string]types.Object{}
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &us_type)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("domain_mode"), &domain_mode)...)
+	for _, name := range []string{"saml", "oidc"} {
+		var block types.Object
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &block)...)
+		blocks[name] = block
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !domain_mode.IsNull() && !domain_mode.IsUnknown() && !slices.Contains(us_domain_modes, domain_mode.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("domain_mode"),
+			"Invalid domain mode",
+			fmt.Sprintf("domain_mode must be \"authoritative\" or \"non_authoritative\", got %q.", domain_mode.ValueString()),
+		)
+	}
+
+	if !blocks["saml"].IsNull() && !blocks["oidc"].IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("oidc"),
+			"Conflicting user source settings",
+			"Only one of the saml and oidc blocks can be set.",
+		)
+		return
+	}
+
+	for name, block := range blocks {
+		if block.IsNull() || block.IsUnknown() {
+			continue
+		}
+		values := block.Attributes()
+
+		if name == "saml" && !values["idp_metadata_xml"].IsNull() && !values["idp_metadata_url"].IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name).AtName("idp_metadata_url"),
+				"Conflicting IdP metadata",
+				"Only one of idp_metadata_xml and idp_metadata_url can be set.",
+			)
+		}
+
+		for _, attribute := range us_url_attributes[name] {
+			value, ok := values[attribute].(types.String)
+			if !ok || value.IsUnknown() || value.ValueString() == "" {
+				continue
+			}
+			if err := validateURL(value.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(name).AtName(attribute),
+					"Invalid URL",
+					fmt.Sprintf("%s must be an absolute http or https URL: %s", attribute, err),
+				)
+			}
+		}
+
+		if name == "oidc" && !values["client_secret_wo"].IsNull() {
+			if isConfigured(values["client_secret"]) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(name).AtName("client_secret_wo"),
+					"Conflicting client secrets",
+					"Only one of client_secret and client_secret_wo can be set.",
+				)
+			}
+			if values["client_secret_version"].IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(name).AtName("client_secret_version"),
+					"Missing required attribute",
+					"client_secret_version is required with client_secret_wo, change it to send a new secret.",
+				)
+			}
+		} else if name == "oidc" && !values["client_secret_version"].IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name).AtName("client_secret_version"),
+				"Invalid attribute combination",
+				"client_secret_version only applies to client_secret_wo.",
+			)
+		}
+
+		// endpoints can come from metadata or discovery instead of the configuration
+		required := []string{"client_id"}
+		if name == "saml" {
+			required = nil
+			if values["idp_metadata_xml"].IsNull() && values["idp_metadata_url"].IsNull() {
+				required = []string{"entity_id", "login_url"}
+			}
+		} else if values["discovery_url"].IsNull() {
+			required = append(required, "auth_uri", "token_uri")
+		}
+		for _, attribute := range required {
+			if !isConfigured(values[attribute]) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(name).AtName(attribute),
+					"Missing required attribute",
+					fmt.Sprintf("%s is required in the %s block.", attribute, name),
+				)
+			}
+		}
+	}
+
+	if us_type.IsUnknown() || us_type.IsNull() {
+		return
+	}
+	// the type is matched case-insensitively, as formatUsObj sends it
+	if _, ok := type_str_map[strings.ToLower(us_type.ValueString())]; !ok {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid user source type",
+			fmt.Sprintf("type must be \"saml\" or \"oidc\", got %q.", us_type.ValueString()),
+		)
+		return
+	}
+
+	for name, block := range blocks {
+		if name == strings.ToLower(us_type.ValueString()) {
+			if block.IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(name),
+					"Missing user source settings",
+					fmt.Sprintf("A %s block is required when type = %q.", name, us_type.ValueString()),
+				)
+			}
+		} else if !block.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Settings block not supported by user source type",
+				fmt.Sprintf("The %s block only applies to %s user sources, but type is %q.", name, strings.ToUpper(name), us_type.ValueString()),
+			)
+		}
+	}
+}
+
+// isConfigured reports whether a config value is set, empty strings count as unset.
+func isConfigured(value attr.Value) bool {
+	if value == nil || value.IsNull() {
+		return false
+	}
+	if value, ok := value.(types.String); ok && !value.IsUnknown() {
+		return value.ValueString() != ""
+	}
+	return true
+}
+
+// validateURL checks that value is an absolute http or https URL.
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host")
+	}
+	return nil
+}
+
+// ModifyPlan fills the saml block from idp_metadata_xml or idp_metadata_url, the oidc block
+// endpoints from discovery_url, and derives the signing certificate fingerprint and expiry,
+// flagging a certificate that has expired or expires soon.
+func (r *userSourceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to fill on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var saml, oidc types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("saml"), &saml)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("oidc"), &oidc)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !oidc.IsNull() && !oidc.IsUnknown() {
+		r.planOIDCDiscovery(ctx, req, resp)
+		r.planClientSecret(ctx, req, resp)
+	}
+	if saml.IsNull() || saml.IsUnknown() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.planIdPMetadata(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	base := path.Root("saml")
+	var signing_cert types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, base.AtName("signing_cert"), &signing_cert)...)
+	fingerprint, expiry := types.StringUnknown(), types.StringUnknown()
+	if !signing_cert.IsUnknown() {
+		if signing_cert.ValueString() != "" {
+			if _, err := parseCertificatePEM(signing_cert.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					base.AtName("signing_cert"),
+					"Invalid signing certificate",
+					"Could not parse signing_cert as a PEM encoded X.509 certificate: "+err.Error(),
+				)
+				return
+			}
+		}
+		resp.Diagnostics.Append(checkCertificateExpiry(signing_cert.ValueString(), base.AtName("signing_cert"), r.certExpiryWarningDays, true)...)
+		fingerprint, expiry = certificateDetails(signing_cert.ValueString())
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, base.AtName("signing_cert_fingerprint"), fingerprint)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, base.AtName("signing_cert_expiry"), expiry)...)
+}
+
+// planIdPMetadata plans the saml block, filling it from the IdP metadata when it is set.
+func (r *userSourceResource) planIdPMetadata(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	base := path.Root("saml")
+	var metadata_xml, metadata_url types.String
+	var post_binding types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, base.AtName("idp_metadata_xml"), &metadata_xml)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, base.AtName("idp_metadata_url"), &metadata_url)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, base.AtName("post_binding"), &post_binding)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// SAML settings the metadata can fill
+	fields := []string{"entity_id", "login_url", "logout_url", "signing_cert"}
+	values := map[string]string{}
+
+	// metadata that is only known at apply time leaves the filled attributes unknown
+	if metadata_xml.IsUnknown() || metadata_url.IsUnknown() {
+		resp.Diagnostics.Append(planUnknown(ctx, req.Config, &resp.Plan, base, fields)...)
+		if post_binding.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, base.AtName("post_binding"), types.BoolUnknown())...)
+		}
+		return
+	}
+
+	source := base.AtName("idp_metadata_xml")
+	if !metadata_url.IsNull() {
+		source = base.AtName("idp_metadata_url")
+	}
+	data, err := loadMetadata(ctx, r.client, metadata_xml, metadata_url)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(source, "Error fetching IdP metadata", err.Error())
+		return
+	}
+
+	if len(data) > 0 {
+		metadata, err := parseIdPMetadata(data)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				source,
+				"Invalid IdP metadata",
+				"Could not parse SAML IdP metadata: "+err.Error(),
+			)
+			return
+		}
+		// an explicit post_binding picks the SSO endpoint, otherwise HTTP-Redirect is preferred
+		login_url, post := metadata.loginURL(post_binding.ValueBool())
+		values["entity_id"] = metadata.EntityID
+		values["login_url"] = login_url
+		values["logout_url"] = metadata.LogoutURL
+		values["signing_cert"] = metadata.signingCert()
+		if post_binding.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, base.AtName("post_binding"), types.BoolValue(post))...)
+		}
+	}
+
+	defaults := map[string]string{"entity_id": "", "login_url": "", "logout_url": "", "signing_cert": ""}
+	resp.Diagnostics.Append(planFromMetadata(ctx, req.Config, &resp.Plan, base, fields, values, defaults)...)
+}
+
+// planOIDCDiscovery plans the oidc block endpoints, filling the ones not set in the
+// configuration from the discovery document when discovery_url is set.
+func (r *userSourceResource) planOIDCDiscovery(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	base := path.Root("oidc")
+	var discovery_url, issuer types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, base.AtName("discovery_url"), &discovery_url)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, base.AtName("issuer"), &issuer)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// OIDC endpoints the discovery document can fill
+	fields := []string{"auth_uri", "token_uri", "userinfo_uri", "logout_uri", "issuer"}
+	values := map[string]string{}
+
+	if discovery_url.IsUnknown() {
+		resp.Diagnostics.Append(planUnknown(ctx, req.Config, &resp.Plan, base, fields)...)
+		return
+	}
+
+	if discovery_url.ValueString() != "" {
+		var httpClient *http.Client
+		if r.client != nil {
+			httpClient = r.client.HTTPClient
+		}
+		discovery, err := discoverOIDC(ctx, httpClient, discovery_url.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				base.AtName("discovery_url"),
+				"Error reading OIDC discovery document",
+				err.Error(),
+			)
+			return
+		}
+		if !issuer.IsNull() && !issuer.IsUnknown() && issuer.ValueString() != discovery.Issuer {
+			resp.Diagnostics.AddAttributeError(
+				base.AtName("issuer"),
+				"OIDC issuer mismatch",
+				fmt.Sprintf("issuer is set to %q but the discovery document at %s has %q.", issuer.ValueString(), discovery_url.ValueString(), discovery.Issuer),
+			)
+			return
+		}
+
+		values["auth_uri"] = discovery.AuthorizationEndpoint
+		values["token_uri"] = discovery.TokenEndpoint
+		values["userinfo_uri"] = discovery.UserinfoEndpoint
+		values["logout_uri"] = discovery.EndSessionEndpoint
+		values["issuer"] = discovery.Issuer
+
+		// explicitly set endpoints override the discovered ones
+		for _, field := range fields {
+			var configured types.String
+			resp.Diagnostics.Append(req.Config.GetAttribute(ctx, base.AtName(field), &configured)...)
+			if !configured.IsNull() {
+				delete(values, field)
+			}
+		}
+	}
+
+	defaults := map[string]string{"auth_uri": "", "token_uri": "", "userinfo_uri": "", "logout_uri": "", "issuer": ""}
+	resp.Diagnostics.Append(planFromMetadata(ctx, req.Config, &resp.Plan, base, fields, values, defaults)...)
+}
+
+// planClientSecret warns when client_secret_wo no longer matches the secret last sent but
+// client_secret_version is unchanged, as the new secret would not be sent.
+func (r *userSourceResource) planClientSecret(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	base := path.Root("oidc")
+	var client_secret_wo types.String
+	var version, state_version types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, base.AtName("client_secret_wo"), &client_secret_wo)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, base.AtName("client_secret_version"), &version)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, base.AtName("client_secret_version"), &state_version)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if client_secret_wo.IsNull() || client_secret_wo.IsUnknown() || !version.Equal(state_version) {
+		return
+	}
+
+	hash, diags := clientSecretHash(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if hash != "" && hash != hashClientSecret(client_secret_wo.ValueString()) {
+		resp.Diagnostics.AddAttributeWarning(
+			base.AtName("client_secret_wo"),
+			"Client secret changed without a version change",
+			"client_secret_wo differs from the secret last sent to FortiToken Cloud, but client_secret_version is unchanged so the new secret will not be sent. Change client_secret_version to rotate the secret.",
+		)
+	}
 }
 
 // Create a new resource.
@@ -332,10 +900,17 @@ func (r *userSourceResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	if plan.Oidc != nil {
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("oidc").AtName("client_secret_wo"), &plan.Oidc.ClientSecretWO)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	obj, domain_ids := formatUsObj(plan, true)
 
 	// Create new user source
-	usersource, err := r.client.CreateUserSource(obj)
+	usersource, err := r.client.CreateUserSourceWithContext(ctx, obj)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating user source",
@@ -344,7 +919,7 @@ func (r *userSourceResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	usersource, err = r.client.GetUserSource(usersource.ID)
+	usersource, err = r.client.GetUserSourceWithContext(ctx, usersource.ID)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading user source",
@@ -353,42 +928,18 @@ func (r *userSourceResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	attr_mapping, _ := json.Marshal(usersource.AttrMapping)
-	plan = userSourceResourceModel{
-		ID:                         types.StringValue(usersource.ID),
-		Name:                       types.StringValue(usersource.Name),
-		Type:                       types.StringValue(type_int_map[int64(usersource.Type)]),
-		EntityID:                   types.StringValue(usersource.EntityID),
-		LoginUrl:                   types.StringValue(usersource.LoginUrl),
-		LogoutUrl:                  types.StringValue(usersource.LogoutUrl),
-		AuthUri:                    types.StringValue(usersource.AuthUri),
-		TokenUri:                   types.StringValue(usersource.TokenUri),
-		UserInfoUri:                types.StringValue(usersource.UserInfoUri),
-		LogoutUri:                  types.StringValue(usersource.LogoutUri),
-		Issuer:                     types.StringValue(usersource.Issuer),
-		ClientID:                   types.StringValue(usersource.ClientID),
-		ClientSecret:               types.StringValue(plan.ClientSecret.ValueString()),
-		RealmID:                    types.StringValue(usersource.RealmID),
-		Prefix:                     types.StringValue(usersource.Prefix),
-		PostBinding:                types.BoolValue(usersource.PostBinding),
-		IncludeSubject:             types.BoolValue(usersource.IncludeSubject),
-		UsernameAssertion:          types.StringValue(usersource.UsernameAssertion),
-		LoginHint:                  types.StringValue(usersource.LoginHint),
-		ProxyEntityID:              types.StringValue(usersource.ProxySP.EntityID),
-		ProxyAcsUrl:                types.StringValue(usersource.ProxySP.AcsUrl),
-		ProxySloUrl:                types.StringValue(usersource.ProxySP.SloUrl),
-		ProxySSoUrl:                types.StringValue(usersource.ProxySP.SsoUrl),
-		ProxyCallbackUrl:           types.StringValue(usersource.ProxySP.CallbackUrl),
-		ProxyPostLogoutRedirectUri: types.StringValue(usersource.ProxySP.PostLogoutRedirectUrl),
-		ProxyOidcLoginUrl:          types.StringValue(usersource.ProxySP.OidcLoginUrl),
-		AttrMapping:                types.StringValue(string(attr_mapping)),
-		Domains:                    make([]types.String, 0),
-	}
+	resp.Diagnostics.Append(recordClientSecret(ctx, resp.Private, obj)...)
+	resp.Diagnostics.Append(recordAttrMapping(ctx, resp.Private, usersource.AttrMapping)...)
+
+	plan = userSourceResourceModelFrom(usersource, plan)
+	plan.Domains = make([]types.String, 0)
 
 	// Set state to fully populated data
 	resp.State.Set(ctx, plan)
 
-	_, err = r.client.UpdateUserSourceDomains(usersource.ID, domain_ids)
+	err = userSourceDomains.update(ctx, r.client, usersource.ID, func(current []string) []string {
+		return userSourceDomainIDs(current, domain_ids["domain_ids"], nil, plan.DomainMode.ValueString())
+	})
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -417,61 +968,33 @@ func (r *userSourceResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	// if resource has been deleted upstream, reset state
-	if state.ID.ValueString() == "" {
-		state = userSourceResourceModel{}
-	} else {
-		// Get refreshed user source
-		usersource, err := r.client.GetUserSource(state.ID.ValueString())
-		if err != nil {
-			if !strings.Contains(err.Error(), "status: 404") {
-				resp.Diagnostics.AddError(
-					"Error Reading user source",
-					"Could not read User Source ID "+state.ID.ValueString()+": "+err.Error(),
-				)
-				return
-			}
-			state = userSourceResourceModel{}
-		} else {
-			// Overwrite items with refreshed state
-			attr_mapping, _ := json.Marshal(usersource.AttrMapping)
-			domain_list := make([]types.String, 0)
-			for _, domain := range usersource.Domains {
-				domain_list = append(domain_list, types.StringValue(domain.ID))
-			}
-			new_secret := state.ClientSecret
-			if state.ClientSecret.ValueString() == "" {
-				new_secret = types.StringValue(usersource.ClientSecret)
-			}
-			state = userSourceResourceModel{
-				ID:                         types.StringValue(usersource.ID),
-				Name:                       types.StringValue(usersource.Name),
-				Type:                       types.StringValue(type_int_map[int64(usersource.Type)]),
-				EntityID:                   types.StringValue(usersource.EntityID),
-				LoginUrl:                   types.StringValue(usersource.LoginUrl),
-				LogoutUrl:                  types.StringValue(usersource.LogoutUrl),
-				AuthUri:                    types.StringValue(usersource.AuthUri),
-				TokenUri:                   types.StringValue(usersource.TokenUri),
-				UserInfoUri:                types.StringValue(usersource.UserInfoUri),
-				LogoutUri:                  types.StringValue(usersource.LogoutUri),
-				Issuer:                     types.StringValue(usersource.Issuer),
-				ClientID:                   types.StringValue(usersource.ClientID),
-				ClientSecret:               new_secret,
-				RealmID:                    types.StringValue(usersource.RealmID),
-				Prefix:                     types.StringValue(usersource.Prefix),
-				PostBinding:                types.BoolValue(usersource.PostBinding),
-				IncludeSubject:             types.BoolValue(usersource.IncludeSubject),
-				UsernameAssertion:          types.StringValue(usersource.UsernameAssertion),
-				LoginHint:                  types.StringValue(usersource.LoginHint),
-				ProxyEntityID:              types.StringValue(usersource.ProxySP.EntityID),
-				ProxyAcsUrl:                types.StringValue(usersource.ProxySP.AcsUrl),
-				ProxySloUrl:                types.StringValue(usersource.ProxySP.SloUrl),
-				ProxySSoUrl:                types.StringValue(usersource.ProxySP.SsoUrl),
-				ProxyCallbackUrl:           types.StringValue(usersource.ProxySP.CallbackUrl),
-				ProxyPostLogoutRedirectUri: types.StringValue(usersource.ProxySP.PostLogoutRedirectUrl),
-				ProxyOidcLoginUrl:          types.StringValue(usersource.ProxySP.OidcLoginUrl),
-				AttrMapping:                types.StringValue(string(attr_mapping)),
-				Domains:                    domain_list,
+	usersource, err := r.client.GetUserSourceWithContext(ctx, state.ID.ValueString())
+	if ftc_client.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading user source",
+			"Could not read user source ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	state = userSourceResourceModelFrom(usersource, state)
+	resp.Diagnostics.Append(recordAttrMapping(ctx, resp.Private, usersource.AttrMapping)...)
+
+	// a secret that no longer matches the one sent was rotated outside Terraform, FTC may
+	// also withhold or mask it, which says nothing about a rotation
+	if state.Oidc != nil && clientSecretReturned(usersource.ClientSecret) {
+		hash, diags := clientSecretHash(ctx, req.Private)
+		resp.Diagnostics.Append(diags...)
+		if hash != "" && hash != hashClientSecret(usersource.ClientSecret) {
+			if state.Oidc.ClientSecretVersion.IsNull() {
+				state.Oidc.ClientSecret = types.StringValue(usersource.ClientSecret)
+			} else {
+				state.Oidc.ClientSecretVersion = types.Int64Null()
 			}
 		}
 	}
@@ -501,15 +1024,27 @@ func (r *userSourceResource) Update(ctx context.Context, req resource.UpdateRequ
 	old_domains := state.Domains
 	new_domains := plan.Domains
 
+	// the write-only secret is only sent when its version changes
+	if plan.Oidc != nil && (state.Oidc == nil || plan.ID.ValueString() == "" || !plan.Oidc.ClientSecretVersion.Equal(state.Oidc.ClientSecretVersion)) {
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("oidc").AtName("client_secret_wo"), &plan.Oidc.ClientSecretWO)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	obj, domain_ids := formatUsObj(plan, false)
+	resp.Diagnostics.Append(restoreAttrMappingTypes(ctx, req.Private, obj)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	if plan.ID.ValueString() == "" {
 		// Create new user source if not found
 		obj, domain_ids = formatUsObj(plan, true)
-		usersource, err = r.client.CreateUserSource(obj)
+		usersource, err = r.client.CreateUserSourceWithContext(ctx, obj)
 	} else {
 		// Update existing user source
-		usersource, err = r.client.UpdateUserSource(plan.ID.ValueString(), obj)
+		usersource, err = r.client.UpdateUserSourceWithContext(ctx, plan.ID.ValueString(), obj)
 	}
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -519,7 +1054,7 @@ func (r *userSourceResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
-	usersource, err = r.client.GetUserSource(usersource.ID)
+	usersource, err = r.client.GetUserSourceWithContext(ctx, usersource.ID)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading user source",
@@ -528,40 +1063,25 @@ func (r *userSourceResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
-	attr_mapping, _ := json.Marshal(usersource.AttrMapping)
-	plan = userSourceResourceModel{
-		ID:                         types.StringValue(usersource.ID),
-		Name:                       types.StringValue(usersource.Name),
-		Type:                       types.StringValue(type_int_map[int64(usersource.Type)]),
-		EntityID:                   types.StringValue(usersource.EntityID),
-		LoginUrl:                   types.StringValue(usersource.LoginUrl),
-		LogoutUrl:                  types.StringValue(usersource.LogoutUrl),
-		AuthUri:                    types.StringValue(usersource.AuthUri),
-		TokenUri:                   types.StringValue(usersource.TokenUri),
-		UserInfoUri:                types.StringValue(usersource.UserInfoUri),
-		LogoutUri:                  types.StringValue(usersource.LogoutUri),
-		Issuer:                     types.StringValue(usersource.Issuer),
-		ClientID:                   types.StringValue(usersource.ClientID),
-		ClientSecret:               types.StringValue(plan.ClientSecret.ValueString()),
-		RealmID:                    types.StringValue(usersource.RealmID),
-		Prefix:                     types.StringValue(usersource.Prefix),
-		PostBinding:                types.BoolValue(usersource.PostBinding),
-		IncludeSubject:             types.BoolValue(usersource.IncludeSubject),
-		UsernameAssertion:          types.StringValue(usersource.UsernameAssertion),
-		LoginHint:                  types.StringValue(usersource.LoginHint),
-		ProxyEntityID:              types.StringValue(usersource.ProxySP.EntityID),
-		ProxyAcsUrl:                types.StringValue(usersource.ProxySP.AcsUrl),
-		ProxySloUrl:                types.StringValue(usersource.ProxySP.SloUrl),
-		ProxySSoUrl:                types.StringValue(usersource.ProxySP.SsoUrl),
-		ProxyCallbackUrl:           types.StringValue(usersource.ProxySP.CallbackUrl),
-		ProxyPostLogoutRedirectUri: types.StringValue(usersource.ProxySP.PostLogoutRedirectUrl),
-		ProxyOidcLoginUrl:          types.StringValue(usersource.ProxySP.OidcLoginUrl),
-		AttrMapping:                types.StringValue(string(attr_mapping)),
-		Domains:                    old_domains,
-	}
+	resp.Diagnostics.Append(recordClientSecret(ctx, resp.Private, obj)...)
+	resp.Diagnostics.Append(recordAttrMapping(ctx, resp.Private, usersource.AttrMapping)...)
 
-	if !reflect.DeepEqual(new_domains, old_domains) {
-		_, err = r.client.UpdateUserSourceDomains(plan.ID.ValueString(), domain_ids)
+	plan = userSourceResourceModelFrom(usersource, plan)
+	plan.Domains = old_domains
+
+	if !reflect.DeepEqual(new_domains, old_domains) || !plan.DomainMode.Equal(state.DomainMode) {
+		// switching to non-authoritative keeps the domains listed until now attached
+		var removed []string
+		if state.DomainMode.ValueString() == "non_authoritative" {
+			for _, domain_id := range old_domains {
+				if !slices.Contains(domain_ids["domain_ids"], domain_id.ValueString()) {
+					removed = append(removed, domain_id.ValueString())
+				}
+			}
+		}
+		err = userSourceDomains.update(ctx, r.client, plan.ID.ValueString(), func(current []string) []string {
+			return userSourceDomainIDs(current, domain_ids["domain_ids"], removed, plan.DomainMode.ValueString())
+		})
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Updating application",
@@ -588,10 +1108,10 @@ func (r *userSourceResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
-	// Delete existing user source
+	// Delete existing user source, a 404 means it is already gone
 	if state.ID.ValueString() != "" {
-		err := r.client.DeleteUserSource(state.ID.ValueString())
-		if err != nil {
+		err := r.client.DeleteUserSourceWithContext(ctx, state.ID.ValueString())
+		if err != nil && !ftc_client.IsNotFound(err) {
 			resp.Diagnostics.AddError(
 				"Error Deleting UserSource",
 				"Could not delete users ource, unexpected error: "+err.Error(),
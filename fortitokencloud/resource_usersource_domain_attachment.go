@@ -0,0 +1,37 @@
+package fortitokencloud
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// userSourceDomains is the list of domains attached to a user source.
+var userSourceDomains = &attachment{
+	typeName:    "_usersource_domain_attachment",
+	description: "Attaches a single domain to a user source, leaving other domains alone.",
+	mapping:     "user source domain",
+	parent:      attachmentSide{attribute: "user_source_id", name: "user source"},
+	child:       attachmentSide{attribute: "domain_id", name: "domain"},
+	locks:       &userSourceLocks,
+	get: func(ctx context.Context, client *ftc_client.Client, us_id string) ([]string, error) {
+		usersource, err := client.GetUserSourceWithContext(ctx, us_id)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, 0, len(usersource.Domains))
+		for _, domain := range usersource.Domains {
+			ids = append(ids, domain.ID)
+		}
+		return ids, nil
+	},
+	set: func(ctx context.Context, client *ftc_client.Client, us_id string, ids []string) error {
+		_, err := client.UpdateUserSourceDomainsWithContext(ctx, us_id, map[string][]string{"domain_ids": ids})
+		return err
+	},
+}
+
+// NewUserSourceDomainAttachmentResource is a helper function to simplify the provider implementation.
+func NewUserSourceDomainAttachmentResource() resource.Resource {
+	return &attachmentResource{attachment: userSourceDomains}
+}
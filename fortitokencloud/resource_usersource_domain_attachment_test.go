@@ -0,0 +1,192 @@
+package fortitokencloud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"terraform-provider-fortitokencloud/sdk/ftctest"
+)
+
+func TestUserSourceDomainIDs(t *testing.T) {
+	current := []string{"listed", "attached", "removed"}
+	cases := []struct {
+		domain_mode string
+		want        []string
+	}{
+		{"authoritative", []string{"listed", "new"}},
+		{"non_authoritative", []string{"attached", "listed", "new"}},
+	}
+	for _, c := range cases {
+		got := userSourceDomainIDs(current, []string{"listed", "new"}, []string{"removed"}, c.domain_mode)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.domain_mode, got, c.want)
+		}
+	}
+}
+
+// testAccUserSourceDomainAttachmentConfig lists the corp domain on a user source and, when
+// attached is set, attaches the lab domain through an attachment resource.
+func testAccUserSourceDomainAttachmentConfig(domain_mode string, attached bool) string {
+	config := fmt.Sprintf(`
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_domain" "corp" {
+  name     = "corp.example.com"
+  realm_id = data.fortitokencloud_realm.test.id
+}
+
+resource "fortitokencloud_domain" "lab" {
+  name     = "lab.example.com"
+  realm_id = data.fortitokencloud_realm.test.id
+}
+
+resource "fortitokencloud_usersource" "test" {
+  name        = "okta"
+  type        = "oidc"
+  realm_id    = data.fortitokencloud_realm.test.id
+  domain_mode = %q
+  domain_ids  = [fortitokencloud_domain.corp.id]
+
+  oidc {
+    auth_uri      = "https://okta.example.com/oauth2/v1/authorize"
+    token_uri     = "https://okta.example.com/oauth2/v1/token"
+    client_id     = "okta"
+    client_secret = "secret"
+  }
+}
+`, domain_mode)
+	if attached {
+		config += `
+resource "fortitokencloud_usersource_domain_attachment" "lab" {
+  user_source_id = fortitokencloud_usersource.test.id
+  domain_id      = fortitokencloud_domain.lab.id
+}
+`
+	}
+	return config
+}
+
+// testAccCheckUserSourceDomains checks which of the corp and lab domains are attached to the
+// user source on the server.
+func testAccCheckUserSourceDomains(srv *ftctest.Server, names ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		us_id, err := testAccUserSourceID(s)
+		if err != nil {
+			return err
+		}
+		for _, name := range []string{"corp", "lab"} {
+			rs, ok := s.RootModule().Resources["fortitokencloud_domain."+name]
+			if !ok {
+				return fmt.Errorf("fortitokencloud_domain.%s not found in state", name)
+			}
+			domain, ok := srv.Domain(rs.Primary.ID)
+			if !ok {
+				return fmt.Errorf("domain %s does not exist", rs.Primary.ID)
+			}
+			want := false
+			for _, attached := range names {
+				want = want || attached == name
+			}
+			if got := domain.UserSourceID == us_id; got != want {
+				return fmt.Errorf("domain %s attached = %v, want %v", name, got, want)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccUserSourceDomainAttachmentResource(t *testing.T) {
+	srv, client := testAccServer(t)
+	var us_id, corp_id, lab_id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckUserSourceDestroy(srv),
+		Steps: []resource.TestStep{
+			// the user source leaves the attached domain alone
+			{
+				Config: srv.ProviderConfig() + testAccUserSourceDomainAttachmentConfig("non_authoritative", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "domain_ids.#", "1"),
+					resource.TestCheckResourceAttrPair("fortitokencloud_usersource_domain_attachment.lab", "domain_id", "fortitokencloud_domain.lab", "id"),
+					testAccCheckUserSourceDomains(srv, "corp", "lab"),
+					func(s *terraform.State) error {
+						us_id = s.RootModule().Resources["fortitokencloud_usersource.test"].Primary.ID
+						corp_id = s.RootModule().Resources["fortitokencloud_domain.corp"].Primary.ID
+						lab_id = s.RootModule().Resources["fortitokencloud_domain.lab"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "fortitokencloud_usersource_domain_attachment.lab",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  "fortitokencloud_usersource_domain_attachment.lab",
+				ImportState:   true,
+				ImportStateId: "missing-slash",
+				ExpectError:   regexp.MustCompile(`Unexpected Import Identifier`),
+			},
+			// a domain detached outside Terraform is attached again
+			{
+				PreConfig: func() {
+					_, err := client.UpdateUserSourceDomainsWithContext(context.Background(), us_id, map[string][]string{"domain_ids": {corp_id}})
+					if err != nil {
+						t.Fatalf("detaching lab domain: %s", err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccUserSourceDomainAttachmentConfig("non_authoritative", true),
+				Check:  testAccCheckUserSourceDomains(srv, "corp", "lab"),
+			},
+			// removing the attachment detaches only its domain
+			{
+				Config: srv.ProviderConfig() + testAccUserSourceDomainAttachmentConfig("non_authoritative", false),
+				Check:  testAccCheckUserSourceDomains(srv, "corp"),
+			},
+			// an authoritative user source detaches domains attached outside Terraform
+			{
+				PreConfig: func() {
+					_, err := client.UpdateUserSourceDomainsWithContext(context.Background(), us_id, map[string][]string{"domain_ids": {lab_id}})
+					if err != nil {
+						t.Fatalf("attaching lab domain: %s", err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccUserSourceDomainAttachmentConfig("authoritative", false),
+				Check:  testAccCheckUserSourceDomains(srv, "corp"),
+			},
+		},
+	})
+}
+
+func TestAccUserSourceDomainAttachmentResourceErrors(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      srv.ProviderConfig() + testAccUserSourceDomainAttachmentConfig("sometimes", false),
+				ExpectError: regexp.MustCompile(`Invalid domain mode`),
+			},
+			{
+				Config: srv.ProviderConfig() + `
+resource "fortitokencloud_usersource_domain_attachment" "test" {
+  user_source_id = "missing"
+  domain_id      = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Could not attach domain missing to user source\s+missing`),
+			},
+		},
+	})
+}
@@ -0,0 +1,772 @@
+package fortitokencloud
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"terraform-provider-fortitokencloud/sdk/ftctest"
+)
+
+func testAccUserSourceSamlConfig(us_type, login_url string) string {
+	return fmt.Sprintf(`
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_domain" "test" {
+  name     = "example.com"
+  realm_id = data.fortitokencloud_realm.test.id
+}
+
+resource "fortitokencloud_usersource" "test" {
+  name               = "azure"
+  type               = %q
+  realm_id           = data.fortitokencloud_realm.test.id
+  domain_ids         = [fortitokencloud_domain.test.id]
+  username_assertion = "username"
+  attr_mapping = {
+    Username = "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"
+  }
+
+  saml {
+    entity_id = "https://sts.windows.net/tenant/"
+    login_url = %q
+  }
+}
+`, us_type, login_url)
+}
+
+func testAccUserSourceOidcConfig(client_id string) string {
+	return fmt.Sprintf(`
+data "fortitokencloud_realm" "test" {
+  name = "default"
+}
+
+resource "fortitokencloud_usersource" "test" {
+  name     = "okta"
+  type     = "oidc"
+  realm_id = data.fortitokencloud_realm.test.id
+
+  oidc {
+    auth_uri      = "https://okta.example.com/oauth2/v1/authorize"
+    token_uri     = "https://okta.example.com/oauth2/v1/token"
+    client_id     = %q
+    client_secret = "secret"
+  }
+}
+`, client_id)
+}
+
+// testAccUserSourceID returns the ID of fortitokencloud_usersource.test in state.
+func testAccUserSourceID(s *terraform.State) (string, error) {
+	rs, ok := s.RootModule().Resources["fortitokencloud_usersource.test"]
+	if !ok {
+		return "", fmt.Errorf("fortitokencloud_usersource.test not found in state")
+	}
+	return rs.Primary.ID, nil
+}
+
+func testAccCheckUserSourceDestroy(srv *ftctest.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "fortitokencloud_usersource" {
+				continue
+			}
+			if _, ok := srv.UserSource(rs.Primary.ID); ok {
+				return fmt.Errorf("user source %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccUserSourceResourceSaml(t *testing.T) {
+	srv, client := testAccServer(t)
+	var us_id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckUserSourceDestroy(srv),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: srv.ProviderConfig() + testAccUserSourceSamlConfig("saml", "https://login.example.com/saml2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "type", "saml"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.login_url", "https://login.example.com/saml2"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "domain_ids.#", "1"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "attr_mapping.Username", "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"),
+					resource.TestCheckResourceAttrPair("fortitokencloud_usersource.test", "domain_ids.0", "fortitokencloud_domain.test", "id"),
+					resource.TestCheckResourceAttrSet("fortitokencloud_usersource.test", "proxy_acs_url"),
+					func(s *terraform.State) error {
+						id, err := testAccUserSourceID(s)
+						if err != nil {
+							return err
+						}
+						us, ok := srv.UserSource(id)
+						if !ok {
+							return fmt.Errorf("user source %s does not exist", id)
+						}
+						if us.Type != 1 || us.LoginUrl != "https://login.example.com/saml2" {
+							return fmt.Errorf("user source on server has type %d and login URL %q", us.Type, us.LoginUrl)
+						}
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "fortitokencloud_usersource.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: srv.ProviderConfig() + testAccUserSourceSamlConfig("saml", "https://login.example.com/saml2/v2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.login_url", "https://login.example.com/saml2/v2"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "domain_ids.#", "1"),
+					func(s *terraform.State) (err error) {
+						us_id, err = testAccUserSourceID(s)
+						return err
+					},
+				),
+			},
+			// a user source deleted outside Terraform is created again
+			{
+				PreConfig: func() {
+					if err := client.DeleteUserSourceWithContext(context.Background(), us_id); err != nil {
+						t.Fatalf("deleting user source: %s", err)
+					}
+				},
+				Config: srv.ProviderConfig() + testAccUserSourceSamlConfig("saml", "https://login.example.com/saml2/v2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "domain_ids.#", "1"),
+					func(s *terraform.State) error {
+						id, err := testAccUserSourceID(s)
+						if err != nil {
+							return err
+						}
+						if id == us_id {
+							return fmt.Errorf("user source %s was not created again", id)
+						}
+						if _, ok := srv.UserSource(id); !ok {
+							return fmt.Errorf("user source %s does not exist", id)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:  "fortitokencloud_usersource.test",
+				ImportState:   true,
+				ImportStateId: "missing",
+				ExpectError:   regexp.MustCompile(`Cannot import non-existent remote object`),
+			},
+		},
+	})
+}
+
+func TestAccUserSourceResourceTypeCase(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	// The configured casing is kept, so the plan after apply is empty
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckUserSourceDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccUserSourceSamlConfig("SAML", "https://login.example.com/saml2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "type", "SAML"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.entity_id", "https://sts.windows.net/tenant/"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserSourceResourceOidc(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckUserSourceDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: srv.ProviderConfig() + testAccUserSourceOidcConfig("client-1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "type", "oidc"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.client_id", "client-1"),
+					resource.TestCheckResourceAttrSet("fortitokencloud_usersource.test", "proxy_callback_url"),
+				),
+			},
+			{
+				Config: srv.ProviderConfig() + testAccUserSourceOidcConfig("client-2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.client_id", "client-2"),
+					func(s *terraform.State) error {
+						id, err := testAccUserSourceID(s)
+						if err != nil {
+							return err
+						}
+						us, _ := srv.UserSource(id)
+						if us.ClientID != "client-2" || us.ClientSecret != "secret" {
+							return fmt.Errorf("user source on server has client ID %q and secret %q", us.ClientID, us.ClientSecret)
+						}
+						return nil
+					},
+				),
+			},
+			// FTC never returns the client secret
+			{
+				ResourceName:            "fortitokencloud_usersource.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"oidc.client_secret"},
+			},
+		},
+	})
+}
+
+func TestAccUserSourceResourceAPIErrors(t *testing.T) {
+	srv, client := testAccServer(t)
+
+	if _, err := client.CreateUserSourceWithContext(context.Background(), map[string]interface{}{
+		"name":     "okta",
+		"type":     2,
+		"realm_id": srv.DefaultRealmID(),
+		"oidc_params": map[string]interface{}{
+			"auth_uri":  "https://okta.example.com/oauth2/v1/authorize",
+			"token_uri": "https://okta.example.com/oauth2/v1/token",
+			"client_id": "client",
+		},
+	}); err != nil {
+		t.Fatalf("unable to create user source: %s", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckUserSourceDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config:      srv.ProviderConfig() + testAccUserSourceOidcConfig("client"),
+				ExpectError: regexp.MustCompile(`(?s)Error creating user source.*status: 409`),
+			},
+		},
+	})
+}
+
+func TestAccUserSourceResourceIdPMetadata(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	der := testCertificate(t, "idp.example.com", time.Date(2031, 5, 4, 0, 0, 0, 0, time.UTC))
+	metadata := testIdPMetadata("https://idp.example.com", der)
+	metadataSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, strings.Replace(metadata, "/saml2/logout", "/saml2/logout/v2", 1))
+	}))
+	defer metadataSrv.Close()
+
+	config := func(settings string) string {
+		return srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_usersource" "test" {
+  name               = "idp"
+  type               = "saml"
+  realm_id           = %q
+  username_assertion = "username"
+
+  saml {
+    %s
+  }
+}
+`, srv.DefaultRealmID(), settings)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckUserSourceDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config(fmt.Sprintf("idp_metadata_xml = %q", metadata)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.entity_id", "https://idp.example.com"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.login_url", "https://idp.example.com/saml2/redirect"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.logout_url", "https://idp.example.com/saml2/logout"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.post_binding", "false"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.signing_cert", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))),
+					resource.TestMatchResourceAttr("fortitokencloud_usersource.test", "saml.signing_cert_fingerprint", regexp.MustCompile(`^([0-9A-F]{2}:){31}[0-9A-F]{2}$`)),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.signing_cert_expiry", "2031-05-04T00:00:00Z"),
+				),
+			},
+			// an explicit post_binding picks the HTTP-POST endpoint
+			{
+				Config: config(fmt.Sprintf("idp_metadata_url = %q\n    post_binding = true", metadataSrv.URL+"/")),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.login_url", "https://idp.example.com/saml2/post"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.logout_url", "https://idp.example.com/saml2/logout/v2"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "saml.post_binding", "true"),
+					func(s *terraform.State) error {
+						id, err := testAccUserSourceID(s)
+						if err != nil {
+							return err
+						}
+						us, _ := srv.UserSource(id)
+						if us.LoginUrl != "https://idp.example.com/saml2/post" || !us.PostBinding || us.SigningCert == "" {
+							return fmt.Errorf("user source on server has login URL %q, post binding %t and signing certificate %q", us.LoginUrl, us.PostBinding, us.SigningCert)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:      config(fmt.Sprintf("idp_metadata_url = %q\n    entity_id = %q", metadataSrv.URL+"/", "https://other.example.com")),
+				ExpectError: regexp.MustCompile(`(?s)Metadata conflict.*entity_id is set to`),
+			},
+			{
+				Config:      config(fmt.Sprintf("idp_metadata_url = %q\n    idp_metadata_xml = %q", metadataSrv.URL+"/", metadata)),
+				ExpectError: regexp.MustCompile(`Only one of idp_metadata_xml and idp_metadata_url can be set`),
+			},
+			{
+				Config:      config(fmt.Sprintf("idp_metadata_url = %q", metadataSrv.URL+"/missing")),
+				ExpectError: regexp.MustCompile(`(?s)Error fetching IdP metadata.*status: 404`),
+			},
+			{
+				Config:      config("entity_id = \"https://idp.example.com\"\n    login_url = \"https://idp.example.com/saml2\"\n    signing_cert = \"not a certificate\""),
+				ExpectError: regexp.MustCompile(`(?s)Invalid signing certificate`),
+			},
+			{
+				Config:      config(fmt.Sprintf("idp_metadata_xml = %q", testIdPMetadata("https://idp.example.com", testCertificate(t, "idp.example.com", time.Now().Add(-time.Hour))))),
+				ExpectError: regexp.MustCompile(`(?s)Certificate expired.*"CN=idp.example.com" expired on`),
+			},
+		},
+	})
+}
+
+func TestAccUserSourceResourceOIDCDiscovery(t *testing.T) {
+	srv, _ := testAccServer(t)
+	discoverySrv := testDiscoveryServer(t)
+
+	config := func(settings string) string {
+		return srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_usersource" "test" {
+  name          = "okta"
+  type          = "oidc"
+  realm_id      = %q
+
+  oidc {
+    client_id     = "client"
+    client_secret = "secret"
+    %s
+  }
+}
+`, srv.DefaultRealmID(), settings)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckUserSourceDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config(fmt.Sprintf("discovery_url = %q", discoverySrv.URL)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.issuer", discoverySrv.URL),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.auth_uri", discoverySrv.URL+"/authorize"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.token_uri", discoverySrv.URL+"/token"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.userinfo_uri", discoverySrv.URL+"/userinfo"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.logout_uri", discoverySrv.URL+"/logout"),
+				),
+			},
+			// explicitly set endpoints override the discovered ones
+			{
+				Config: config(fmt.Sprintf("discovery_url = %q\n    token_uri = %q", discoverySrv.URL, "https://proxy.example.com/token")),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.token_uri", "https://proxy.example.com/token"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.auth_uri", discoverySrv.URL+"/authorize"),
+					func(s *terraform.State) error {
+						id, err := testAccUserSourceID(s)
+						if err != nil {
+							return err
+						}
+						us, _ := srv.UserSource(id)
+						if us.TokenUri != "https://proxy.example.com/token" || us.AuthUri != discoverySrv.URL+"/authorize" {
+							return fmt.Errorf("user source on server has token URI %q and auth URI %q", us.TokenUri, us.AuthUri)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:      config(fmt.Sprintf("discovery_url = %q\n    issuer = %q", discoverySrv.URL, "https://other.example.com")),
+				ExpectError: regexp.MustCompile(`(?s)OIDC issuer mismatch`),
+			},
+			{
+				Config:      config(fmt.Sprintf("discovery_url = %q", discoverySrv.URL+"/missing")),
+				ExpectError: regexp.MustCompile(`(?s)Error reading OIDC discovery document.*status:\s+404`),
+			},
+		},
+	})
+}
+
+func TestAccUserSourceResourceValidation(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name: "unknown type",
+			config: `
+resource "fortitokencloud_usersource" "test" {
+  name     = "us"
+  type     = "ldap"
+  realm_id = "realm"
+}
+`,
+			err: `type must be "saml" or "oidc", got "ldap"`,
+		},
+		{
+			name: "missing settings block",
+			config: `
+resource "fortitokencloud_usersource" "test" {
+  name     = "us"
+  type     = "saml"
+  realm_id = "realm"
+}
+`,
+			err: `A saml block is required when type = "saml"`,
+		},
+		{
+			name: "missing settings block with mixed-case type",
+			config: `
+resource "fortitokencloud_usersource" "test" {
+  name     = "us"
+  type     = "Oidc"
+  realm_id = "realm"
+}
+`,
+			err: `A oidc block is required when type = "Oidc"`,
+		},
+		{
+			name: "both settings blocks",
+			config: `
+resource "fortitokencloud_usersource" "test" {
+  name     = "us"
+  type     = "saml"
+  realm_id = "realm"
+
+  saml {
+    entity_id = "https://idp.example.com/"
+    login_url = "https://idp.example.com/saml2"
+  }
+
+  oidc {
+    auth_uri  = "https://idp.example.com/authorize"
+    token_uri = "https://idp.example.com/token"
+    client_id = "client"
+  }
+}
+`,
+			err: `Only one of the saml and oidc blocks can be set`,
+		},
+		{
+			name: "block of the other type",
+			config: `
+resource "fortitokencloud_usersource" "test" {
+  name     = "us"
+  type     = "saml"
+  realm_id = "realm"
+
+  oidc {
+    auth_uri  = "https://idp.example.com/authorize"
+    token_uri = "https://idp.example.com/token"
+    client_id = "client"
+  }
+}
+`,
+			err: `The oidc block only applies to OIDC user sources`,
+		},
+		{
+			name: "missing required SAML attribute",
+			config: `
+resource "fortitokencloud_usersource" "test" {
+  name     = "us"
+  type     = "saml"
+  realm_id = "realm"
+
+  saml {
+    entity_id = "https://idp.example.com/"
+  }
+}
+`,
+			err: `login_url is required in the saml block`,
+		},
+		{
+			name: "missing required OIDC attribute",
+			config: `
+resource "fortitokencloud_usersource" "test" {
+  name     = "us"
+  type     = "oidc"
+  realm_id = "realm"
+
+  oidc {
+    client_id = "client"
+    auth_uri  = "https://idp.example.com/authorize"
+  }
+}
+`,
+			err: `token_uri is required in the oidc block`,
+		},
+		{
+			name: "relative URL",
+			config: `
+resource "fortitokencloud_usersource" "test" {
+  name     = "us"
+  type     = "saml"
+  realm_id = "realm"
+
+  saml {
+    entity_id = "https://idp.example.com/"
+    login_url = "/saml2"
+  }
+}
+`,
+			err: `login_url must be an absolute http or https URL`,
+		},
+		{
+			name: "unsupported scheme",
+			config: `
+resource "fortitokencloud_usersource" "test" {
+  name     = "us"
+  type     = "oidc"
+  realm_id = "realm"
+
+  oidc {
+    client_id     = "client"
+    discovery_url = "ftp://idp.example.com/"
+  }
+}
+`,
+			err: `unsupported scheme "ftp"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config:      srv.ProviderConfig() + tt.config,
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(regexp.QuoteMeta(tt.err)),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccUserSourceResourceClientSecretWO(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	config := func(secret string, version int) string {
+		return srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_usersource" "test" {
+  name     = "okta"
+  type     = "oidc"
+  realm_id = %q
+
+  oidc {
+    auth_uri              = "https://okta.example.com/oauth2/v1/authorize"
+    token_uri             = "https://okta.example.com/oauth2/v1/token"
+    client_id             = "client"
+    client_secret_wo      = %q
+    client_secret_version = %d
+  }
+}
+`, srv.DefaultRealmID(), secret, version)
+	}
+	checkSecret := func(want string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			id, err := testAccUserSourceID(s)
+			if err != nil {
+				return err
+			}
+			if us, _ := srv.UserSource(id); us.ClientSecret != want {
+				return fmt.Errorf("client secret on server is %q, want %q", us.ClientSecret, want)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		CheckDestroy: testAccCheckUserSourceDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config("secret-1", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("fortitokencloud_usersource.test", "oidc.client_secret_wo"),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.client_secret", ""),
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.client_secret_version", "1"),
+					checkSecret("secret-1"),
+				),
+			},
+			// a new secret without a version change is not sent
+			{
+				Config: config("secret-2", 1),
+				Check:  checkSecret("secret-1"),
+			},
+			{
+				Config: config("secret-2", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.client_secret_version", "2"),
+					checkSecret("secret-2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserSourceResourceClientSecretReturned(t *testing.T) {
+	config := func(srv *ftctest.Server) string {
+		return srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_usersource" "test" {
+  name     = "okta"
+  type     = "oidc"
+  realm_id = %q
+
+  oidc {
+    auth_uri      = "https://okta.example.com/oauth2/v1/authorize"
+    token_uri     = "https://okta.example.com/oauth2/v1/token"
+    client_id     = "client"
+    client_secret = "secret"
+  }
+}
+`, srv.DefaultRealmID())
+	}
+
+	// a masked secret is not taken for a rotation, the plan stays empty
+	t.Run("masked", func(t *testing.T) {
+		srv, _ := testAccServer(t)
+		srv.ClientSecretView = func(secret string) string { return strings.Repeat("*", len(secret)) }
+		resource.Test(t, resource.TestCase{
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             testAccCheckUserSourceDestroy(srv),
+			Steps: []resource.TestStep{
+				{
+					Config: config(srv),
+					Check:  resource.TestCheckResourceAttr("fortitokencloud_usersource.test", "oidc.client_secret", "secret"),
+				},
+			},
+		})
+	})
+
+	// a returned secret that differs from the one sent was rotated outside Terraform
+	t.Run("returned", func(t *testing.T) {
+		srv, client := testAccServer(t)
+		srv.ClientSecretView = func(secret string) string { return secret }
+		var us_id string
+		resource.Test(t, resource.TestCase{
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             testAccCheckUserSourceDestroy(srv),
+			Steps: []resource.TestStep{
+				{
+					Config: config(srv),
+					Check: func(s *terraform.State) (err error) {
+						us_id, err = testAccUserSourceID(s)
+						return err
+					},
+				},
+				{
+					PreConfig: func() {
+						_, err := client.UpdateUserSourceWithContext(context.Background(), us_id, map[string]interface{}{
+							"oidc_params": map[string]interface{}{"client_secret": "rotated"},
+						})
+						if err != nil {
+							t.Fatalf("rotating the client secret: %s", err)
+						}
+					},
+					Config:             config(srv),
+					PlanOnly:           true,
+					ExpectNonEmptyPlan: true,
+				},
+			},
+		})
+	})
+}
+
+func TestAccUserSourceResourceClientSecretWOValidation(t *testing.T) {
+	srv, _ := testAccServer(t)
+
+	tests := []struct {
+		name     string
+		settings string
+		err      string
+	}{
+		{
+			name:     "both secrets",
+			settings: "client_secret = \"secret\"\n    client_secret_wo = \"secret\"\n    client_secret_version = 1",
+			err:      `Only one of client_secret and client_secret_wo can be set`,
+		},
+		{
+			name:     "write-only secret without version",
+			settings: `client_secret_wo = "secret"`,
+			err:      `client_secret_version is required with client_secret_wo`,
+		},
+		{
+			name:     "version without write-only secret",
+			settings: `client_secret_version = 1`,
+			err:      `client_secret_version only applies to client_secret_wo`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+					tfversion.SkipBelow(tfversion.Version1_11_0),
+				},
+				Steps: []resource.TestStep{
+					{
+						Config: srv.ProviderConfig() + fmt.Sprintf(`
+resource "fortitokencloud_usersource" "test" {
+  name     = "okta"
+  type     = "oidc"
+  realm_id = "realm"
+
+  oidc {
+    auth_uri  = "https://okta.example.com/oauth2/v1/authorize"
+    token_uri = "https://okta.example.com/oauth2/v1/token"
+    client_id = "client"
+    %s
+  }
+}
+`, tt.settings),
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(regexp.QuoteMeta(tt.err)),
+					},
+				},
+			})
+		})
+	}
+}
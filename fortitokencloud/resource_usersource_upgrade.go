@@ -0,0 +1,137 @@
+package fortitokencloud
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// userSourceSchemaV1 is the flat user source schema used before the saml and oidc blocks. Only
+// the attribute types matter when decoding prior state.
+func userSourceSchemaV1() schema.Schema {
+	attributes := map[string]schema.Attribute{
+		"post_binding": schema.BoolAttribute{
+			Optional: true,
+			Computed: true,
+		},
+		"include_subject": schema.BoolAttribute{
+			Optional: true,
+			Computed: true,
+		},
+		"attr_mapping": schema.MapAttribute{
+			Optional:    true,
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"domain_ids": schema.SetAttribute{
+			Optional:    true,
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+	}
+	for _, name := range []string{
+		"id", "name", "type", "realm_id", "prefix", "username_assertion", "login_hint",
+		"entity_id", "login_url", "logout_url", "signing_cert", "signing_cert_fingerprint", "signing_cert_expiry",
+		"idp_metadata_xml", "idp_metadata_url",
+		"auth_uri", "token_uri", "userinfo_uri", "logout_uri", "issuer", "discovery_url", "client_id", "client_secret",
+		"proxy_entity_id", "proxy_acs_url", "proxy_slo_url", "proxy_sso_url", "proxy_callback_url",
+		"proxy_post_logout_redirect_uri", "proxy_oidc_login_url",
+	} {
+		attributes[name] = schema.StringAttribute{
+			Optional: true,
+			Computed: true,
+		}
+	}
+	return schema.Schema{Version: 1, Attributes: attributes}
+}
+
+// upgradeUserSourceV0 upgrades flat state with attr_mapping stored as a JSON string.
+func upgradeUserSourceV0() resource.StateUpgrader {
+	prior := attrMappingPriorSchema(userSourceSchemaV1())
+
+	return resource.StateUpgrader{
+		PriorSchema: &prior,
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			values := map[string]tftypes.Value{}
+			if err := req.State.Raw.As(&values); err != nil {
+				resp.Diagnostics.AddError("Unable to upgrade state", err.Error())
+				return
+			}
+
+			resp.Diagnostics.Append(upgradeAttrMappingValue(values)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			nestUserSourceState(ctx, values, resp)
+		},
+	}
+}
+
+// upgradeUserSourceV1 upgrades flat state to the saml and oidc blocks.
+func upgradeUserSourceV1() resource.StateUpgrader {
+	prior := userSourceSchemaV1()
+
+	return resource.StateUpgrader{
+		PriorSchema: &prior,
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			values := map[string]tftypes.Value{}
+			if err := req.State.Raw.As(&values); err != nil {
+				resp.Diagnostics.AddError("Unable to upgrade state", err.Error())
+				return
+			}
+
+			nestUserSourceState(ctx, values, resp)
+		},
+	}
+}
+
+// nestUserSourceState moves the flat SAML or OIDC attributes into the block matching the user
+// source type, the attributes of the other type are dropped.
+func nestUserSourceState(ctx context.Context, values map[string]tftypes.Value, resp *resource.UpgradeStateResponse) {
+	var us_type string
+	if !values["type"].IsNull() {
+		if err := values["type"].As(&us_type); err != nil {
+			resp.Diagnostics.AddError("Unable to upgrade state", err.Error())
+			return
+		}
+	}
+
+	target, ok := resp.State.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		resp.Diagnostics.AddError("Unable to upgrade state", "user source schema is not an object")
+		return
+	}
+
+	upgraded := map[string]tftypes.Value{}
+	for name, attribute_type := range target.AttributeTypes {
+		if name != "saml" && name != "oidc" {
+			value, ok := values[name]
+			if !ok {
+				value = tftypes.NewValue(attribute_type, nil)
+			}
+			upgraded[name] = value
+			continue
+		}
+		block_type := attribute_type.(tftypes.Object)
+		if strings.ToLower(us_type) != name {
+			upgraded[name] = tftypes.NewValue(block_type, nil)
+			continue
+		}
+		block := map[string]tftypes.Value{}
+		for child, child_type := range block_type.AttributeTypes {
+			value, ok := values[child]
+			if !ok {
+				value = tftypes.NewValue(child_type, nil)
+			}
+			block[child] = value
+		}
+		upgraded[name] = tftypes.NewValue(block_type, block)
+	}
+
+	resp.State.Raw = tftypes.NewValue(target, upgraded)
+}
@@ -0,0 +1,90 @@
+package fortitokencloud
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUserSourceUpgradeStateV1(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		block  string
+		other  string
+		values map[string]string
+	}{
+		{
+			name: "saml",
+			raw: `{
+				"id": "us-1", "name": "azure", "type": "saml", "realm_id": "realm-1", "prefix": "abc",
+				"username_assertion": "username", "attr_mapping": {"Username": "email"}, "domain_ids": ["d-1"],
+				"entity_id": "https://sts.windows.net/tenant/", "login_url": "https://login.example.com/saml2",
+				"logout_url": "", "post_binding": true, "include_subject": false,
+				"auth_uri": "", "token_uri": "", "client_id": "", "client_secret": "",
+				"proxy_acs_url": "https://ftc.example.com/saml-sp/abc/acs/"
+			}`,
+			block: "saml",
+			other: "oidc",
+			values: map[string]string{
+				"entity_id": "https://sts.windows.net/tenant/",
+				"login_url": "https://login.example.com/saml2",
+			},
+		},
+		{
+			name: "oidc with upper case type",
+			raw: `{
+				"id": "us-2", "name": "okta", "type": "OIDC", "realm_id": "realm-1",
+				"attr_mapping": {}, "domain_ids": [],
+				"entity_id": "", "login_url": "",
+				"auth_uri": "https://okta.example.com/authorize", "token_uri": "https://okta.example.com/token",
+				"client_id": "client", "client_secret": "secret"
+			}`,
+			block: "oidc",
+			other: "saml",
+			values: map[string]string{
+				"auth_uri":      "https://okta.example.com/authorize",
+				"client_id":     "client",
+				"client_secret": "secret",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, diags := testUpgradeState(t, "fortitokencloud_usersource", 1, tt.raw)
+			testNoErrors(t, diags)
+
+			for attribute, want := range tt.values {
+				if got := testStateString(t, state, tt.block, attribute); got != want {
+					t.Errorf("%s.%s = %q, want %q", tt.block, attribute, got, want)
+				}
+			}
+			if other := testStateValue(t, state, tt.other); !other.IsNull() {
+				t.Errorf("%s block = %s, want null", tt.other, other)
+			}
+			// attributes outside the blocks are kept as they were
+			if got := testStateString(t, state, "realm_id"); got != "realm-1" {
+				t.Errorf("realm_id = %q, want realm-1", got)
+			}
+		})
+	}
+}
+
+func TestUserSourceUpgradeStateV0(t *testing.T) {
+	state, diags := testUpgradeState(t, "fortitokencloud_usersource", 0, `{
+		"id": "us-1", "name": "azure", "type": "saml", "realm_id": "realm-1",
+		"attr_mapping": "{\"Username\": \"email\", \"groups\": [\"a\", \"b\"]}",
+		"domain_ids": ["d-1"],
+		"entity_id": "https://sts.windows.net/tenant/", "login_url": "https://login.example.com/saml2"
+	}`)
+	testNoErrors(t, diags)
+
+	if mapping := testStateMap(t, state, "attr_mapping"); !reflect.DeepEqual(mapping, map[string]string{"Username": "email", "groups": `["a","b"]`}) {
+		t.Errorf("attr_mapping = %v, want Username and groups", mapping)
+	}
+	if got := testStateString(t, state, "saml", "login_url"); got != "https://login.example.com/saml2" {
+		t.Errorf("saml.login_url = %q, want the flat login_url", got)
+	}
+	if other := testStateValue(t, state, "oidc"); !other.IsNull() {
+		t.Errorf("oidc block = %s, want null", other)
+	}
+}
@@ -0,0 +1,421 @@
+package fortitokencloud
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+const (
+	samlMetadataNamespace = "urn:oasis:names:tc:SAML:2.0:metadata"
+	samlProtocol          = "urn:oasis:names:tc:SAML:2.0:protocol"
+	xmlDSigNamespace      = "http://www.w3.org/2000/09/xmldsig#"
+	samlBindingRedirect   = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	samlBindingPost       = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+
+	// maxMetadataSize caps how much of a metadata document is read from a URL
+	maxMetadataSize = 1 << 20
+)
+
+// samlNameIDFormats are the NameID formats defined by SAML 1.1 and 2.0 that FTC can issue.
+var samlNameIDFormats = []string{
+	"urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified",
+	"urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress",
+	"urn:oasis:names:tc:SAML:1.1:nameid-format:X509SubjectName",
+	"urn:oasis:names:tc:SAML:1.1:nameid-format:WindowsDomainQualifiedName",
+	"urn:oasis:names:tc:SAML:2.0:nameid-format:kerberos",
+	"urn:oasis:names:tc:SAML:2.0:nameid-format:entity",
+	"urn:oasis:names:tc:SAML:2.0:nameid-format:persistent",
+	"urn:oasis:names:tc:SAML:2.0:nameid-format:transient",
+}
+
+// samlEntitiesDescriptor is the aggregate some federations publish instead of a single entity.
+type samlEntitiesDescriptor struct {
+	XMLName           xml.Name               `xml:"EntitiesDescriptor"`
+	EntityDescriptors []samlEntityDescriptor `xml:"EntityDescriptor"`
+}
+
+// samlEntityDescriptor maps the parts of a SAML 2.0 EntityDescriptor the provider reads.
+type samlEntityDescriptor struct {
+	XMLName           xml.Name            `xml:"EntityDescriptor"`
+	EntityID          string              `xml:"entityID,attr"`
+	SPSSODescriptors  []samlSSODescriptor `xml:"SPSSODescriptor"`
+	IDPSSODescriptors []samlSSODescriptor `xml:"IDPSSODescriptor"`
+}
+
+// samlSSODescriptor holds the role descriptor elements shared by SPs and IdPs.
+type samlSSODescriptor struct {
+	KeyDescriptors            []samlKeyDescriptor `xml:"KeyDescriptor"`
+	NameIDFormats             []string            `xml:"NameIDFormat"`
+	SingleLogoutServices      []samlEndpoint      `xml:"SingleLogoutService"`
+	AssertionConsumerServices []samlEndpoint      `xml:"AssertionConsumerService"`
+	SingleSignOnServices      []samlEndpoint      `xml:"SingleSignOnService"`
+}
+
+type samlKeyDescriptor struct {
+	Use          string   `xml:"use,attr"`
+	Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+type samlEndpoint struct {
+	Binding   string `xml:"Binding,attr"`
+	Location  string `xml:"Location,attr"`
+	Index     int    `xml:"index,attr"`
+	IsDefault string `xml:"isDefault,attr"`
+}
+
+// spMetadata is what the application resource takes from an SP metadata document.
+type spMetadata struct {
+	EntityID     string
+	AcsURL       string
+	SloURL       string
+	NameIDFormat string
+	SigningCert  string
+}
+
+// idpMetadata is what the user source resource takes from an IdP metadata document.
+type idpMetadata struct {
+	EntityID     string
+	RedirectURL  string
+	PostURL      string
+	LogoutURL    string
+	SigningCerts []string
+}
+
+// loginURL returns the SSO endpoint for the requested binding, falling back to the other one.
+// The second value reports whether the returned endpoint uses the HTTP-POST binding.
+func (md *idpMetadata) loginURL(post bool) (string, bool) {
+	if post && md.PostURL != "" || md.RedirectURL == "" {
+		return md.PostURL, md.PostURL != ""
+	}
+	return md.RedirectURL, false
+}
+
+// signingCert picks the currently valid signing certificate that expires last, so a
+// rollover certificate published ahead of time is not used before its validity starts.
+func (md *idpMetadata) signingCert() string {
+	now := time.Now()
+	var best string
+	var bestExpiry time.Time
+	for _, pem := range md.SigningCerts {
+		cert, err := parseCertificatePEM(pem)
+		if err != nil || now.Before(cert.NotBefore) {
+			continue
+		}
+		if best == "" || cert.NotAfter.After(bestExpiry) {
+			best, bestExpiry = pem, cert.NotAfter
+		}
+	}
+	if best == "" && len(md.SigningCerts) > 0 {
+		return md.SigningCerts[0]
+	}
+	return best
+}
+
+// parseEntityDescriptors decodes either a single EntityDescriptor or an EntitiesDescriptor.
+func parseEntityDescriptors(data []byte) ([]samlEntityDescriptor, error) {
+	var single samlEntityDescriptor
+	singleErr := xml.Unmarshal(data, &single)
+	if singleErr == nil {
+		return []samlEntityDescriptor{single}, nil
+	}
+
+	var aggregate samlEntitiesDescriptor
+	if err := xml.Unmarshal(data, &aggregate); err != nil {
+		return nil, fmt.Errorf("not a SAML EntityDescriptor: %w", singleErr)
+	}
+	return aggregate.EntityDescriptors, nil
+}
+
+// parseSPMetadata extracts the SP endpoints, NameID format and signing certificate from SAML metadata.
+func parseSPMetadata(data []byte) (*spMetadata, error) {
+	entities, err := parseEntityDescriptors(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entity := range entities {
+		if len(entity.SPSSODescriptors) == 0 {
+			continue
+		}
+		sp := entity.SPSSODescriptors[0]
+		md := &spMetadata{
+			EntityID: strings.TrimSpace(entity.EntityID),
+			AcsURL:   defaultAcs(sp.AssertionConsumerServices),
+			SloURL:   preferredEndpoint(sp.SingleLogoutServices),
+		}
+		if len(sp.NameIDFormats) > 0 {
+			md.NameIDFormat = strings.TrimSpace(sp.NameIDFormats[0])
+		}
+		certs, err := signingCertificates(sp.KeyDescriptors)
+		if err != nil {
+			return nil, err
+		}
+		if len(certs) > 0 {
+			md.SigningCert = certs[0]
+		}
+		if md.EntityID == "" {
+			return nil, fmt.Errorf("EntityDescriptor has no entityID")
+		}
+		return md, nil
+	}
+
+	return nil, fmt.Errorf("metadata does not contain an SPSSODescriptor")
+}
+
+// parseIdPMetadata extracts the IdP entity ID, SSO and SLO endpoints and signing certificates from SAML metadata.
+func parseIdPMetadata(data []byte) (*idpMetadata, error) {
+	entities, err := parseEntityDescriptors(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entity := range entities {
+		if len(entity.IDPSSODescriptors) == 0 {
+			continue
+		}
+		idp := entity.IDPSSODescriptors[0]
+		md := &idpMetadata{
+			EntityID:  strings.TrimSpace(entity.EntityID),
+			LogoutURL: preferredEndpoint(idp.SingleLogoutServices),
+		}
+		for _, endpoint := range idp.SingleSignOnServices {
+			if endpoint.Binding == samlBindingRedirect && md.RedirectURL == "" {
+				md.RedirectURL = strings.TrimSpace(endpoint.Location)
+			}
+			if endpoint.Binding == samlBindingPost && md.PostURL == "" {
+				md.PostURL = strings.TrimSpace(endpoint.Location)
+			}
+		}
+		if md.SigningCerts, err = signingCertificates(idp.KeyDescriptors); err != nil {
+			return nil, err
+		}
+		if md.EntityID == "" {
+			return nil, fmt.Errorf("EntityDescriptor has no entityID")
+		}
+		if md.RedirectURL == "" && md.PostURL == "" {
+			return nil, fmt.Errorf("IDPSSODescriptor has no HTTP-Redirect or HTTP-POST SingleSignOnService")
+		}
+		return md, nil
+	}
+
+	return nil, fmt.Errorf("metadata does not contain an IDPSSODescriptor")
+}
+
+// defaultAcs picks the ACS marked isDefault, then the lowest index HTTP-POST endpoint, then the first one.
+func defaultAcs(endpoints []samlEndpoint) string {
+	var post *samlEndpoint
+	for i, endpoint := range endpoints {
+		if endpoint.IsDefault == "true" || endpoint.IsDefault == "1" {
+			return strings.TrimSpace(endpoint.Location)
+		}
+		if endpoint.Binding == samlBindingPost && (post == nil || endpoint.Index < post.Index) {
+			post = &endpoints[i]
+		}
+	}
+	if post != nil {
+		return strings.TrimSpace(post.Location)
+	}
+	if len(endpoints) > 0 {
+		return strings.TrimSpace(endpoints[0].Location)
+	}
+	return ""
+}
+
+// preferredEndpoint picks the HTTP-Redirect endpoint, then HTTP-POST, then the first one.
+func preferredEndpoint(endpoints []samlEndpoint) string {
+	for _, binding := range []string{samlBindingRedirect, samlBindingPost} {
+		for _, endpoint := range endpoints {
+			if endpoint.Binding == binding {
+				return strings.TrimSpace(endpoint.Location)
+			}
+		}
+	}
+	if len(endpoints) > 0 {
+		return strings.TrimSpace(endpoints[0].Location)
+	}
+	return ""
+}
+
+// signingCertificates returns every certificate usable for signing as PEM.
+func signingCertificates(keys []samlKeyDescriptor) ([]string, error) {
+	var certs []string
+	for _, key := range keys {
+		// a KeyDescriptor without use applies to both signing and encryption
+		if key.Use != "" && key.Use != "signing" {
+			continue
+		}
+		for _, cert := range key.Certificates {
+			pem, err := certificateToPEM(cert)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, pem)
+		}
+	}
+	return certs, nil
+}
+
+// certificateToPEM converts the base64 DER body of an X509Certificate element to PEM.
+func certificateToPEM(b64 string) (string, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(b64), ""))
+	if err != nil {
+		return "", fmt.Errorf("invalid X509Certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return "", fmt.Errorf("invalid X509Certificate: %w", err)
+	}
+	return certificateToPEMString(cert), nil
+}
+
+// idpEntityDescriptor is the IdP EntityDescriptor written by generateIdPMetadata, the
+// element names carry their prefixes because encoding/xml cannot declare them itself.
+type idpEntityDescriptor struct {
+	XMLName  xml.Name `xml:"md:EntityDescriptor"`
+	XMLNSMd  string   `xml:"xmlns:md,attr"`
+	XMLNSDs  string   `xml:"xmlns:ds,attr"`
+	EntityID string   `xml:"entityID,attr"`
+	IDP      struct {
+		WantAuthnRequestsSigned    bool               `xml:"WantAuthnRequestsSigned,attr"`
+		ProtocolSupportEnumeration string             `xml:"protocolSupportEnumeration,attr"`
+		KeyDescriptors             []idpKeyDescriptor `xml:"md:KeyDescriptor"`
+		SingleLogoutServices       []idpEndpoint      `xml:"md:SingleLogoutService"`
+		NameIDFormats              []string           `xml:"md:NameIDFormat"`
+		SingleSignOnServices       []idpEndpoint      `xml:"md:SingleSignOnService"`
+	} `xml:"md:IDPSSODescriptor"`
+}
+
+type idpKeyDescriptor struct {
+	Use         string `xml:"use,attr"`
+	Certificate string `xml:"ds:KeyInfo>ds:X509Data>ds:X509Certificate"`
+}
+
+type idpEndpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
+// generateIdPMetadata writes a SAML 2.0 IdP EntityDescriptor advertising the HTTP-Redirect
+// and HTTP-POST bindings for the SSO and SLO endpoints and cert as signing key.
+func generateIdPMetadata(entityID, ssoURL, sloURL string, cert *x509.Certificate, nameIDFormats []string) (string, error) {
+	md := idpEntityDescriptor{
+		XMLNSMd:  samlMetadataNamespace,
+		XMLNSDs:  xmlDSigNamespace,
+		EntityID: entityID,
+	}
+	md.IDP.ProtocolSupportEnumeration = samlProtocol
+	md.IDP.KeyDescriptors = []idpKeyDescriptor{{"signing", base64.StdEncoding.EncodeToString(cert.Raw)}}
+	for _, binding := range []string{samlBindingRedirect, samlBindingPost} {
+		if sloURL != "" {
+			md.IDP.SingleLogoutServices = append(md.IDP.SingleLogoutServices, idpEndpoint{binding, sloURL})
+		}
+		md.IDP.SingleSignOnServices = append(md.IDP.SingleSignOnServices, idpEndpoint{binding, ssoURL})
+	}
+	md.IDP.NameIDFormats = nameIDFormats
+
+	out, err := xml.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out) + "\n", nil
+}
+
+// loadMetadata returns the inline metadata document, or downloads it when url is set.
+func loadMetadata(ctx context.Context, client *ftc_client.Client, document, url types.String) ([]byte, error) {
+	if url.ValueString() == "" {
+		return []byte(document.ValueString()), nil
+	}
+
+	var httpClient *http.Client
+	if client != nil {
+		httpClient = client.HTTPClient
+	}
+	data, err := fetchMetadata(ctx, httpClient, url.ValueString())
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch metadata from %s: %w", url.ValueString(), err)
+	}
+	return data, nil
+}
+
+// planFromMetadata plans the string attributes in fields, relative to base, from metadata. Attributes set in the
+// configuration must agree with the metadata, unset ones take the metadata value or, failing
+// that, the value in defaults. Fields absent from both maps are left to the plan.
+func planFromMetadata(ctx context.Context, config tfsdk.Config, plan *tfsdk.Plan, base path.Path, fields []string, metadata, defaults map[string]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, field := range fields {
+		var configured types.String
+		diags.Append(config.GetAttribute(ctx, base.AtName(field), &configured)...)
+		if diags.HasError() {
+			return diags
+		}
+		if configured.IsUnknown() {
+			continue
+		}
+
+		value, ok := metadata[field]
+		if value == "" {
+			value, ok = defaults[field]
+		} else if !configured.IsNull() && configured.ValueString() != value {
+			diags.AddAttributeError(
+				base.AtName(field),
+				"Metadata conflict",
+				fmt.Sprintf("%s is set to %q but the metadata has %q. Remove %s from the configuration or make it match the metadata.", field, configured.ValueString(), value, field),
+			)
+			continue
+		}
+		if configured.IsNull() && ok {
+			diags.Append(plan.SetAttribute(ctx, base.AtName(field), types.StringValue(value))...)
+		}
+	}
+	return diags
+}
+
+// planUnknown marks the attributes in fields, relative to base, that are not set in the configuration as unknown.
+func planUnknown(ctx context.Context, config tfsdk.Config, plan *tfsdk.Plan, base path.Path, fields []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, field := range fields {
+		var configured types.String
+		diags.Append(config.GetAttribute(ctx, base.AtName(field), &configured)...)
+		if configured.IsNull() {
+			diags.Append(plan.SetAttribute(ctx, base.AtName(field), types.StringUnknown())...)
+		}
+	}
+	return diags
+}
+
+// fetchMetadata downloads a metadata document, client may be nil before the provider is configured.
+func fetchMetadata(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 399 {
+		return nil, fmt.Errorf("GET %s: status: %d", url, res.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(res.Body, maxMetadataSize))
+}
@@ -0,0 +1,359 @@
+package fortitokencloud
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCertificate returns a self-signed DER certificate for commonName expiring at notAfter.
+func testCertificate(t *testing.T, commonName string, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+// testSPMetadata returns an SP EntityDescriptor whose signing KeyDescriptor holds signing,
+// with an encryption KeyDescriptor when encryption is set.
+func testSPMetadata(entityID string, signing, encryption []byte) string {
+	encryption_key := ""
+	if encryption != nil {
+		encryption_key = fmt.Sprintf(`
+    <md:KeyDescriptor use="encryption">
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>%s</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>`, base64.StdEncoding.EncodeToString(encryption))
+	}
+	return fmt.Sprintf(`<?xml version="1.0"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="%s">
+  <md:SPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">%s
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>
+        %s
+      </ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://sp.example.com/saml/slo/post"/>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://sp.example.com/saml/slo"/>
+    <md:NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</md:NameIDFormat>
+    <md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Artifact" Location="https://sp.example.com/saml/artifact" index="0"/>
+    <md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://sp.example.com/saml/acs" index="1"/>
+  </md:SPSSODescriptor>
+</md:EntityDescriptor>`, entityID, encryption_key, base64.StdEncoding.EncodeToString(signing))
+}
+
+func TestParseSPMetadata(t *testing.T) {
+	der := testCertificate(t, "sp.example.com", time.Now().Add(24*time.Hour))
+	encryption := testCertificate(t, "encryption.sp.example.com", time.Now().Add(24*time.Hour))
+	want := spMetadata{
+		EntityID:     "https://sp.example.com/saml/metadata",
+		AcsURL:       "https://sp.example.com/saml/acs",
+		SloURL:       "https://sp.example.com/saml/slo",
+		NameIDFormat: "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress",
+		SigningCert:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+
+	// the encryption key comes first and must not be taken for the signing certificate
+	single := testSPMetadata("https://sp.example.com/saml/metadata", der, encryption)
+	// federations publish the SP next to other entities
+	aggregate := `<EntitiesDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata">
+  <EntityDescriptor entityID="https://idp.example.com"><IDPSSODescriptor/></EntityDescriptor>
+` + strings.SplitN(single, "?>", 2)[1] + `
+</EntitiesDescriptor>`
+
+	for name, data := range map[string]string{"EntityDescriptor": single, "EntitiesDescriptor": aggregate} {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseSPMetadata([]byte(data))
+			if err != nil {
+				t.Fatalf("parseSPMetadata: %v", err)
+			}
+			if *got != want {
+				t.Errorf("parseSPMetadata = %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestParseSPMetadataErrors(t *testing.T) {
+	der := testCertificate(t, "sp.example.com", time.Now().Add(24*time.Hour))
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "not XML", data: "entityID: sp", wantErr: "not a SAML EntityDescriptor"},
+		{name: "IdP metadata", data: `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="idp"><IDPSSODescriptor/></EntityDescriptor>`, wantErr: "does not contain an SPSSODescriptor"},
+		{name: "no entityID", data: testSPMetadata("", der, nil), wantErr: "has no entityID"},
+		{name: "invalid certificate", data: testSPMetadata("sp", []byte("not a certificate"), nil), wantErr: "invalid X509Certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSPMetadata([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got %v, want an error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultAcs(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []samlEndpoint
+		want      string
+	}{
+		{name: "none"},
+		{name: "isDefault wins", want: "https://sp/default", endpoints: []samlEndpoint{
+			{Binding: samlBindingPost, Location: "https://sp/post", Index: 0},
+			{Binding: samlBindingRedirect, Location: "https://sp/default", Index: 1, IsDefault: "true"},
+		}},
+		{name: "lowest index POST", want: "https://sp/post-1", endpoints: []samlEndpoint{
+			{Binding: samlBindingRedirect, Location: "https://sp/redirect", Index: 0},
+			{Binding: samlBindingPost, Location: "https://sp/post-2", Index: 2},
+			{Binding: samlBindingPost, Location: "https://sp/post-1", Index: 1},
+		}},
+		{name: "first endpoint", want: "https://sp/first", endpoints: []samlEndpoint{
+			{Binding: "urn:oasis:names:tc:SAML:2.0:bindings:PAOS", Location: " https://sp/first "},
+			{Binding: samlBindingRedirect, Location: "https://sp/redirect"},
+		}},
+	}
+	for _, tt := range tests {
+		if got := defaultAcs(tt.endpoints); got != tt.want {
+			t.Errorf("%s: defaultAcs = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPreferredEndpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []samlEndpoint
+		want      string
+	}{
+		{name: "none"},
+		{name: "redirect wins", want: "https://sp/redirect", endpoints: []samlEndpoint{
+			{Binding: samlBindingPost, Location: "https://sp/post"},
+			{Binding: samlBindingRedirect, Location: "https://sp/redirect"},
+		}},
+		{name: "then POST", want: "https://sp/post", endpoints: []samlEndpoint{
+			{Binding: "urn:oasis:names:tc:SAML:2.0:bindings:SOAP", Location: "https://sp/soap"},
+			{Binding: samlBindingPost, Location: "https://sp/post"},
+		}},
+		{name: "first endpoint", want: "https://sp/soap", endpoints: []samlEndpoint{
+			{Binding: "urn:oasis:names:tc:SAML:2.0:bindings:SOAP", Location: "https://sp/soap"},
+		}},
+	}
+	for _, tt := range tests {
+		if got := preferredEndpoint(tt.endpoints); got != tt.want {
+			t.Errorf("%s: preferredEndpoint = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFetchMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metadata":
+			fmt.Fprint(w, "<EntityDescriptor/>")
+		case "/large":
+			w.Write(make([]byte, maxMetadataSize+1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	data, err := fetchMetadata(ctx, nil, srv.URL+"/metadata")
+	if err != nil || string(data) != "<EntityDescriptor/>" {
+		t.Errorf("fetchMetadata = %q, %v", data, err)
+	}
+	if data, err := fetchMetadata(ctx, srv.Client(), srv.URL+"/large"); err != nil || len(data) != maxMetadataSize {
+		t.Errorf("fetchMetadata of a large document read %d bytes (%v), want %d", len(data), err, maxMetadataSize)
+	}
+	if _, err := fetchMetadata(ctx, srv.Client(), srv.URL+"/missing"); err == nil || !strings.Contains(err.Error(), "status: 404") {
+		t.Errorf("fetchMetadata of a missing document: got %v, want a 404 error", err)
+	}
+}
+
+// testIdPMetadata returns an IdP EntityDescriptor publishing certs as signing certificates.
+func testIdPMetadata(entityID string, certs ...[]byte) string {
+	keys := ""
+	for _, der := range certs {
+		keys += fmt.Sprintf(`
+    <KeyDescriptor use="signing"><ds:KeyInfo><ds:X509Data><ds:X509Certificate>%s</ds:X509Certificate></ds:X509Data></ds:KeyInfo></KeyDescriptor>`, base64.StdEncoding.EncodeToString(der))
+	}
+	return fmt.Sprintf(`<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="%s">
+  <IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">%s
+    <SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/saml2/logout"/>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://idp.example.com/saml2/post"/>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/saml2/redirect"/>
+  </IDPSSODescriptor>
+</EntityDescriptor>`, entityID, keys)
+}
+
+func TestParseIdPMetadata(t *testing.T) {
+	der := testCertificate(t, "idp.example.com", time.Now().Add(24*time.Hour))
+
+	md, err := parseIdPMetadata([]byte(testIdPMetadata("https://idp.example.com", der)))
+	if err != nil {
+		t.Fatalf("parseIdPMetadata: %v", err)
+	}
+	want := idpMetadata{
+		EntityID:     "https://idp.example.com",
+		RedirectURL:  "https://idp.example.com/saml2/redirect",
+		PostURL:      "https://idp.example.com/saml2/post",
+		LogoutURL:    "https://idp.example.com/saml2/logout",
+		SigningCerts: []string{string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))},
+	}
+	if fmt.Sprint(*md) != fmt.Sprint(want) {
+		t.Errorf("parseIdPMetadata = %+v, want %+v", *md, want)
+	}
+
+	for _, tt := range []struct {
+		post     bool
+		md       idpMetadata
+		want     string
+		wantPost bool
+	}{
+		{post: false, md: want, want: want.RedirectURL},
+		{post: true, md: want, want: want.PostURL, wantPost: true},
+		{post: false, md: idpMetadata{PostURL: want.PostURL}, want: want.PostURL, wantPost: true},
+		{post: true, md: idpMetadata{RedirectURL: want.RedirectURL}, want: want.RedirectURL},
+	} {
+		if got, post := tt.md.loginURL(tt.post); got != tt.want || post != tt.wantPost {
+			t.Errorf("loginURL(%t) with %+v = %q, %t, want %q, %t", tt.post, tt.md, got, post, tt.want, tt.wantPost)
+		}
+	}
+}
+
+func TestParseIdPMetadataErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "SP metadata", data: testSPMetadata("sp", testCertificate(t, "sp", time.Now().Add(time.Hour)), nil), wantErr: "does not contain an IDPSSODescriptor"},
+		{name: "no entityID", data: testIdPMetadata(""), wantErr: "has no entityID"},
+		{name: "no SSO endpoint", data: `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="idp"><IDPSSODescriptor/></EntityDescriptor>`, wantErr: "no HTTP-Redirect or HTTP-POST SingleSignOnService"},
+		{name: "invalid certificate", data: testIdPMetadata("idp", []byte("not a certificate")), wantErr: "invalid X509Certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseIdPMetadata([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got %v, want an error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIdPSigningCertRollover(t *testing.T) {
+	toPEM := func(der []byte) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+	current := toPEM(testCertificate(t, "current", time.Now().Add(30*24*time.Hour)))
+	older := toPEM(testCertificate(t, "older", time.Now().Add(24*time.Hour)))
+
+	// published ahead of the rollover, valid from tomorrow
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "next"},
+		NotBefore:    time.Now().Add(24 * time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := toPEM(der)
+
+	tests := []struct {
+		name  string
+		certs []string
+		want  string
+	}{
+		{name: "none"},
+		{name: "latest expiry", certs: []string{older, current}, want: current},
+		{name: "not yet valid skipped", certs: []string{next, older, current}, want: current},
+		{name: "only future certificate", certs: []string{next}, want: next},
+	}
+	for _, tt := range tests {
+		md := idpMetadata{SigningCerts: tt.certs}
+		if got := md.signingCert(); got != tt.want {
+			t.Errorf("%s: signingCert picked the wrong certificate", tt.name)
+		}
+	}
+}
+
+func TestGenerateIdPMetadata(t *testing.T) {
+	der := testCertificate(t, "ftc", time.Now().Add(24*time.Hour))
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		sloURL string
+	}{
+		{name: "with SLO", sloURL: "https://ftc.example.com/saml-idp/abc/logout/"},
+		{name: "without SLO"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := generateIdPMetadata("https://ftc.example.com/saml-idp/abc/metadata/", "https://ftc.example.com/saml-idp/abc/login/", tt.sloURL, cert, []string{"urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"})
+			if err != nil {
+				t.Fatalf("generateIdPMetadata: %v", err)
+			}
+			if !strings.Contains(data, `WantAuthnRequestsSigned="false"`) {
+				t.Errorf("generated metadata does not contain WantAuthnRequestsSigned=\"false\":\n%s", data)
+			}
+			if !strings.HasPrefix(data, "<?xml") || !strings.Contains(data, "<md:NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified</md:NameIDFormat>") {
+				t.Errorf("generated metadata:\n%s", data)
+			}
+
+			// the generated document reads back with the IdP metadata parser
+			md, err := parseIdPMetadata([]byte(data))
+			if err != nil {
+				t.Fatalf("parseIdPMetadata of the generated metadata: %v", err)
+			}
+			want := idpMetadata{
+				EntityID:     "https://ftc.example.com/saml-idp/abc/metadata/",
+				RedirectURL:  "https://ftc.example.com/saml-idp/abc/login/",
+				PostURL:      "https://ftc.example.com/saml-idp/abc/login/",
+				LogoutURL:    tt.sloURL,
+				SigningCerts: []string{certificateToPEMString(cert)},
+			}
+			if fmt.Sprint(*md) != fmt.Sprint(want) {
+				t.Errorf("parsed generated metadata = %+v, want %+v", *md, want)
+			}
+		})
+	}
+}
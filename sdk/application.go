@@ -1,6 +1,7 @@
 package ftc_client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,30 +11,60 @@ import (
 
 var appApiPath = "/api/v1/application"
 
-// GetApplications - Returns all applications
+// GetApplications - Calls GetApplicationsWithContext with a background context
 func (c *Client) GetApplications() (*Applications, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", c.HostURL, appApiPath), nil)
+	return c.GetApplicationsWithContext(context.Background())
+}
+
+// GetApplicationsWithContext - Returns all applications, reading every page
+func (c *Client) GetApplicationsWithContext(ctx context.Context) (*Applications, error) {
+	apps, err := c.NewApplicationsPager(ListOptions{}).All(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	return &Applications{Apps: apps}, nil
+}
+
+// NewApplicationsPager - Returns a pager over the applications, starting at opts.Page
+func (c *Client) NewApplicationsPager(opts ListOptions) *Pager[Application] {
+	return newPager(opts, func(app Application) string { return app.ID }, c.getApplicationsPage)
+}
+
+// GetApplicationsPageWithContext - Returns a single page of applications
+func (c *Client) GetApplicationsPageWithContext(ctx context.Context, opts ListOptions) ([]Application, error) {
+	apps, _, err := c.getApplicationsPage(ctx, opts)
+	return apps, err
+}
+
+func (c *Client) getApplicationsPage(ctx context.Context, opts ListOptions) ([]Application, *bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.listURL(appApiPath, opts), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	body, err := c.doRequest(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	apps := Applications{}
-	err = json.Unmarshal(body, &apps.Apps)
+	apps := []Application{}
+	more, err := decodePage(body, &apps)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return &apps, nil
+	return apps, more, nil
 }
 
-// GetApplication - Returns specific application
+// GetApplication - Calls GetApplicationWithContext with a background context
 func (c *Client) GetApplication(AppId string) (*Application, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.HostURL, appApiPath, AppId), nil)
+	return c.GetApplicationWithContext(context.Background(), AppId)
+}
+
+// GetApplicationWithContext - Returns specific application
+func (c *Client) GetApplicationWithContext(ctx context.Context, AppId string) (*Application, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s/%s", c.HostURL, appApiPath, AppId), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -53,14 +84,19 @@ func (c *Client) GetApplication(AppId string) (*Application, error) {
 	return &app, nil
 }
 
-// CreateApplication - Create a new application
+// CreateApplication - Calls CreateApplicationWithContext with a background context
 func (c *Client) CreateApplication(appData interface{}) (*Application, error) {
+	return c.CreateApplicationWithContext(context.Background(), appData)
+}
+
+// CreateApplicationWithContext - Create a new application
+func (c *Client) CreateApplicationWithContext(ctx context.Context, appData interface{}) (*Application, error) {
 	rb, err := json.Marshal(appData)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", c.HostURL, appApiPath), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.HostURL, appApiPath), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
@@ -79,14 +115,19 @@ func (c *Client) CreateApplication(appData interface{}) (*Application, error) {
 	return &app, nil
 }
 
-// UpdateApplication - Updates an application
+// UpdateApplication - Calls UpdateApplicationWithContext with a background context
 func (c *Client) UpdateApplication(appId string, appData interface{}) (*Application, error) {
+	return c.UpdateApplicationWithContext(context.Background(), appId, appData)
+}
+
+// UpdateApplicationWithContext - Updates an application
+func (c *Client) UpdateApplicationWithContext(ctx context.Context, appId string, appData interface{}) (*Application, error) {
 	rb, err := json.Marshal(appData)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s%s/%s", c.HostURL, appApiPath, appId), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s%s/%s", c.HostURL, appApiPath, appId), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
@@ -105,9 +146,14 @@ func (c *Client) UpdateApplication(appId string, appData interface{}) (*Applicat
 	return &app, nil
 }
 
-// DeleteApplication - Deletes an application
+// DeleteApplication - Calls DeleteApplicationWithContext with a background context
 func (c *Client) DeleteApplication(appId string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s%s/%s", c.HostURL, appApiPath, appId), nil)
+	return c.DeleteApplicationWithContext(context.Background(), appId)
+}
+
+// DeleteApplicationWithContext - Deletes an application
+func (c *Client) DeleteApplicationWithContext(ctx context.Context, appId string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s%s/%s", c.HostURL, appApiPath, appId), nil)
 	if err != nil {
 		return err
 	}
@@ -124,8 +170,13 @@ func (c *Client) DeleteApplication(appId string) error {
 	return nil
 }
 
-// UpdateApplicationUserSource - Updates an application's user sources
+// UpdateApplicationUserSource - Calls UpdateApplicationUserSourceWithContext with a background context
 func (c *Client) UpdateApplicationUserSource(appId string, userSourceList map[string][]string) (*[]AppUserMapping, error) {
+	return c.UpdateApplicationUserSourceWithContext(context.Background(), appId, userSourceList)
+}
+
+// UpdateApplicationUserSourceWithContext - Updates an application's user sources
+func (c *Client) UpdateApplicationUserSourceWithContext(ctx context.Context, appId string, userSourceList map[string][]string) (*[]AppUserMapping, error) {
 	// when array is empty, json marshal will convert it to null, need to use make
 	if len(userSourceList["user_source_ids"]) == 0 {
 		userSourceList["user_source_ids"] = make([]string, 0)
@@ -135,7 +186,7 @@ func (c *Client) UpdateApplicationUserSource(appId string, userSourceList map[st
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s%s/%s/user_source", c.HostURL, appApiPath, appId), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s%s/%s/user_source", c.HostURL, appApiPath, appId), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
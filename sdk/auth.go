@@ -1,14 +1,20 @@
 package ftc_client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 )
 
-// SignIn - Get a new token for user
+// SignIn - Calls SignInWithContext with a background context
 func (c *Client) SignIn() (*AuthResponse, error) {
+	return c.SignInWithContext(context.Background())
+}
+
+// SignInWithContext - Get a new token for user
+func (c *Client) SignInWithContext(ctx context.Context) (*AuthResponse, error) {
 	if c.Auth.ClientID == "" || c.Auth.ClientSecret == "" {
 		return nil, fmt.Errorf("define clientid and clientsecret")
 	}
@@ -17,17 +23,22 @@ func (c *Client) SignIn() (*AuthResponse, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v1/login", c.HostURL), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/v1/login", c.HostURL), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.doRequest(req)
-	fmt.Printf(string(body), err)
+	// login is sent without a bearer token, going through doRequest would recurse into the token refresh.
+	// Exchanging credentials has no side effects, so it is retried like an idempotent call.
+	res, body, err := c.do(req, "", true)
 	if err != nil {
 		return nil, err
 	}
 
+	if res.StatusCode > 399 {
+		return nil, newAPIError(req, res, body)
+	}
+
 	ar := AuthResponse{}
 	err = json.Unmarshal(body, &ar)
 	if err != nil {
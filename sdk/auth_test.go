@@ -0,0 +1,145 @@
+package ftc_client_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// tokenServer - Issues a new token on every login and accepts only the latest one
+type tokenServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	expiresIn int
+	rejectAll bool
+	logins    int
+	token     string
+	bodies    []string
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	t.Helper()
+	ts := &tokenServer{expiresIn: expiresIn}
+	ts.Server = httptest.NewServer(http.HandlerFunc(ts.handle))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (ts *tokenServer) handle(w http.ResponseWriter, r *http.Request) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if r.URL.Path == "/api/v1/login" {
+		ts.logins++
+		ts.token = fmt.Sprintf("token-%d", ts.logins)
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": ts.token, "expires_in": ts.expiresIn})
+		return
+	}
+	if ts.rejectAll || r.Header.Get("Authorization") != "Bearer "+ts.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	ts.bodies = append(ts.bodies, string(body))
+	fmt.Fprint(w, `{"id": "app-1", "name": "app"}`)
+}
+
+// revoke - Invalidates the token handed out last, as if FTC expired it early
+func (ts *tokenServer) revoke() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.token = "revoked"
+}
+
+func (ts *tokenServer) loginCount() int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.logins
+}
+
+func newTokenClient(t *testing.T, ts *tokenServer) *ftc_client.Client {
+	t.Helper()
+	host, clientID, clientSecret := ts.URL, "client", "secret"
+	client, err := ftc_client.NewClient(&host, &clientID, &clientSecret)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func TestTokenRefreshedBeforeExpiry(t *testing.T) {
+	// a token expiring within the refresh window is replaced before the request
+	ts := newTokenServer(t, 30)
+	client := newTokenClient(t, ts)
+
+	if _, err := client.GetApplication("app-1"); err != nil {
+		t.Fatalf("GetApplication: %v", err)
+	}
+	if client.Token != "token-2" || ts.loginCount() != 2 {
+		t.Errorf("token = %q after %d logins, want token-2 after 2", client.Token, ts.loginCount())
+	}
+}
+
+func TestTokenKeptUntilRefreshWindow(t *testing.T) {
+	for _, expiresIn := range []int{3600, 0} {
+		t.Run(fmt.Sprintf("expires_in %d", expiresIn), func(t *testing.T) {
+			ts := newTokenServer(t, expiresIn)
+			client := newTokenClient(t, ts)
+
+			for i := 0; i < 3; i++ {
+				if _, err := client.GetApplication("app-1"); err != nil {
+					t.Fatalf("GetApplication: %v", err)
+				}
+			}
+			if ts.loginCount() != 1 {
+				t.Errorf("signed in %d times, want once", ts.loginCount())
+			}
+		})
+	}
+}
+
+func TestSignInAgainOnUnauthorized(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	client := newTokenClient(t, ts)
+
+	ts.revoke()
+	app, err := client.CreateApplication(map[string]string{"name": "app"})
+	if err != nil {
+		t.Fatalf("CreateApplication after the token was revoked: %v", err)
+	}
+	if app.Name != "app" {
+		t.Errorf("application name = %q, want app", app.Name)
+	}
+	if client.Token != "token-2" {
+		t.Errorf("token = %q, want the token of the second login", client.Token)
+	}
+	// the replayed request carries the same body
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if len(ts.bodies) != 1 || ts.bodies[0] != `{"name":"app"}` {
+		t.Errorf("bodies received = %q, want the create payload once", ts.bodies)
+	}
+}
+
+func TestSignInAgainOnlyOnce(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	client := newTokenClient(t, ts)
+
+	// the token handed out by the second login is rejected as well
+	ts.mu.Lock()
+	ts.rejectAll = true
+	ts.mu.Unlock()
+	if _, err := client.GetApplication("app-1"); err == nil {
+		t.Fatal("GetApplication succeeded with every token rejected")
+	}
+	if ts.loginCount() != 2 {
+		t.Errorf("signed in %d times, want 2", ts.loginCount())
+	}
+}
@@ -0,0 +1,171 @@
+package ftc_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var brandingApiPath = "/api/v1/branding"
+
+// GetBrandings - Calls GetBrandingsWithContext with a background context
+func (c *Client) GetBrandings() (*Brandings, error) {
+	return c.GetBrandingsWithContext(context.Background())
+}
+
+// GetBrandingsWithContext - Returns all brandings, reading every page
+func (c *Client) GetBrandingsWithContext(ctx context.Context) (*Brandings, error) {
+	brandings, err := c.NewBrandingsPager(ListOptions{}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Brandings{Brandings: brandings}, nil
+}
+
+// NewBrandingsPager - Returns a pager over the brandings, starting at opts.Page
+func (c *Client) NewBrandingsPager(opts ListOptions) *Pager[Branding] {
+	return newPager(opts, func(branding Branding) string { return branding.ID }, c.getBrandingsPage)
+}
+
+// GetBrandingsPageWithContext - Returns a single page of brandings
+func (c *Client) GetBrandingsPageWithContext(ctx context.Context, opts ListOptions) ([]Branding, error) {
+	brandings, _, err := c.getBrandingsPage(ctx, opts)
+	return brandings, err
+}
+
+func (c *Client) getBrandingsPage(ctx context.Context, opts ListOptions) ([]Branding, *bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.listURL(brandingApiPath, opts), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	brandings := []Branding{}
+	more, err := decodePage(body, &brandings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return brandings, more, nil
+}
+
+// GetBranding - Calls GetBrandingWithContext with a background context
+func (c *Client) GetBranding(brandingId string) (*Branding, error) {
+	return c.GetBrandingWithContext(context.Background(), brandingId)
+}
+
+// GetBrandingWithContext - Returns specific branding
+func (c *Client) GetBrandingWithContext(ctx context.Context, brandingId string) (*Branding, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s/%s", c.HostURL, brandingApiPath, brandingId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	branding := Branding{}
+
+	err = json.Unmarshal(body, &branding)
+	if err != nil {
+		return nil, err
+	}
+
+	return &branding, nil
+}
+
+// CreateBranding - Calls CreateBrandingWithContext with a background context
+func (c *Client) CreateBranding(brandingData interface{}) (*Branding, error) {
+	return c.CreateBrandingWithContext(context.Background(), brandingData)
+}
+
+// CreateBrandingWithContext - Create a new branding
+func (c *Client) CreateBrandingWithContext(ctx context.Context, brandingData interface{}) (*Branding, error) {
+	rb, err := json.Marshal(brandingData)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.HostURL, brandingApiPath), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	branding := Branding{}
+	err = json.Unmarshal(body, &branding)
+	if err != nil {
+		return nil, err
+	}
+
+	return &branding, nil
+}
+
+// UpdateBranding - Calls UpdateBrandingWithContext with a background context
+func (c *Client) UpdateBranding(brandingId string, brandingData interface{}) (*Branding, error) {
+	return c.UpdateBrandingWithContext(context.Background(), brandingId, brandingData)
+}
+
+// UpdateBrandingWithContext - Updates a branding
+func (c *Client) UpdateBrandingWithContext(ctx context.Context, brandingId string, brandingData interface{}) (*Branding, error) {
+	rb, err := json.Marshal(brandingData)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s%s/%s", c.HostURL, brandingApiPath, brandingId), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	branding := Branding{}
+	err = json.Unmarshal(body, &branding)
+	if err != nil {
+		return nil, err
+	}
+
+	return &branding, nil
+}
+
+// DeleteBranding - Calls DeleteBrandingWithContext with a background context
+func (c *Client) DeleteBranding(brandingId string) error {
+	return c.DeleteBrandingWithContext(context.Background(), brandingId)
+}
+
+// DeleteBrandingWithContext - Deletes a branding
+func (c *Client) DeleteBrandingWithContext(ctx context.Context, brandingId string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s%s/%s", c.HostURL, brandingApiPath, brandingId), nil)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	if string(body) != "" {
+		return errors.New(string(body))
+	}
+
+	return nil
+}
@@ -1,21 +1,44 @@
 package ftc_client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 )
 
 // HostURL - Default Hashicups URL
 const HostURL string = "https://ftc.fortinet.com:9696"
 
+// tokenRefreshWindow - Refresh the access token this long before it expires
+const tokenRefreshWindow = 60 * time.Second
+
+// DefaultMaxRetries - Retries attempted for throttled or transient failures
+const DefaultMaxRetries = 3
+
+// DefaultMaxRetryWait - Upper bound for a single wait between retries
+const DefaultMaxRetryWait = 30 * time.Second
+
+// retryBaseWait - First backoff step, doubled on every further retry
+const retryBaseWait = 1 * time.Second
+
 // Client -
 type Client struct {
-	HostURL    string
-	HTTPClient *http.Client
-	Token      string
-	Auth       AuthStruct
+	HostURL      string
+	HTTPClient   *http.Client
+	Token        string
+	Auth         AuthStruct
+	MaxRetries   int
+	MaxRetryWait time.Duration
+
+	// tokenExpiry is zero when FTC did not report a token lifetime
+	tokenExpiry time.Time
+	tokenMu     sync.RWMutex
 }
 
 // AuthStruct -
@@ -30,53 +53,259 @@ type AuthResponse struct {
 	ExpiresIn int    `json:"expires_in"`
 }
 
-// NewClient -
-func NewClient(host, clientId, clientSecret *string) (*Client, error) {
+// ClientOption - Customizes a Client before it signs in
+type ClientOption func(*Client)
+
+// WithRetry - Sets how often and how long throttled or transient failures are retried
+func WithRetry(maxRetries int, maxRetryWait time.Duration) ClientOption {
+	return func(c *Client) {
+		c.MaxRetries = maxRetries
+		c.MaxRetryWait = maxRetryWait
+	}
+}
+
+// NewClient - Calls NewClientWithContext with a background context
+func NewClient(host, clientId, clientSecret *string, opts ...ClientOption) (*Client, error) {
+	return NewClientWithContext(context.Background(), host, clientId, clientSecret, opts...)
+}
+
+// NewClientWithContext - Creates a client and signs in, aborting when ctx is cancelled
+func NewClientWithContext(ctx context.Context, host, clientId, clientSecret *string, opts ...ClientOption) (*Client, error) {
 	c := Client{
-		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
 		// Default Hashicups URL
 		HostURL: HostURL,
 		Auth: AuthStruct{
 			ClientID:     *clientId,
 			ClientSecret: *clientSecret,
 		},
+		MaxRetries:   DefaultMaxRetries,
+		MaxRetryWait: DefaultMaxRetryWait,
 	}
 
 	if host != nil {
 		c.HostURL = *host
 	}
 
-	ar, err := c.SignIn()
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	ar, err := c.SignInWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	c.Token = ar.Token
+	c.setToken(ar)
 
 	return &c, nil
 }
 
+// setToken - Stores a new access token and its expiry, caller must hold tokenMu
+func (c *Client) setToken(ar *AuthResponse) {
+	c.Token = ar.Token
+	c.tokenExpiry = time.Time{}
+	if ar.ExpiresIn > 0 {
+		c.tokenExpiry = time.Now().Add(time.Duration(ar.ExpiresIn) * time.Second)
+	}
+}
+
+// currentToken - Returns the access token, refreshing it first when it is about to expire
+func (c *Client) currentToken(ctx context.Context) (string, error) {
+	c.tokenMu.RLock()
+	token, expiry := c.Token, c.tokenExpiry
+	c.tokenMu.RUnlock()
+
+	if token != "" && (expiry.IsZero() || time.Until(expiry) > tokenRefreshWindow) {
+		return token, nil
+	}
+
+	return c.refreshToken(ctx, token)
+}
+
+// refreshToken - Signs in again unless another request already replaced the stale token
+func (c *Client) refreshToken(ctx context.Context, stale string) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.Token != stale && (c.tokenExpiry.IsZero() || time.Until(c.tokenExpiry) > tokenRefreshWindow) {
+		return c.Token, nil
+	}
+
+	ar, err := c.SignInWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.setToken(ar)
+
+	return c.Token, nil
+}
+
 func (c *Client) doRequest(req *http.Request) ([]byte, error) {
+	token, err := c.currentToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	idempotent := isIdempotent(req.Method)
+	res, body, err := c.do(req, token, idempotent)
+	if err != nil {
+		return nil, err
+	}
+
+	// The token may have been revoked or expired early, sign in once and replay
+	if res.StatusCode == http.StatusUnauthorized {
+		token, err = c.refreshToken(req.Context(), token)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = rewindBody(req); err != nil {
+			return nil, err
+		}
+
+		res, body, err = c.do(req, token, idempotent)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if c.Token != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	if res.StatusCode > 399 {
+		return nil, newAPIError(req, res, body)
+	}
+
+	return body, nil
+}
+
+// do - Sends the request, retrying throttled and transient failures with backoff
+func (c *Client) do(req *http.Request, token string, idempotent bool) (*http.Response, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		res, body, err := c.send(req, token)
+		if attempt >= c.MaxRetries || !shouldRetry(res, err, idempotent) {
+			return res, body, err
+		}
+
+		wait := c.retryWait(attempt, res)
+		// Give up early when the wait would outlive the caller's deadline
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < wait {
+			return res, body, err
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		if err := rewindBody(req); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// send - Executes a single request and returns the response with its body already read
+func (c *Client) send(req *http.Request, token string) (*http.Response, []byte, error) {
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	}
 	req.Header.Set("Content-Type", "application/json")
 
+	ctx := c.logContext(req.Context(), token)
+	logRequest(ctx, req)
+
+	start := time.Now()
 	res, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, err
+		logResponse(ctx, req, nil, nil, time.Since(start), err)
+		return nil, nil, err
 	}
 	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
+	logResponse(ctx, req, res, body, time.Since(start), err)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if res.StatusCode > 399 {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+	return res, body, nil
+}
+
+// isIdempotent - Reports whether a request with this method can safely be sent twice
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry - Decides whether a failed attempt is worth repeating
+func shouldRetry(res *http.Response, err error, idempotent bool) bool {
+	if err != nil {
+		// Cancellation is final, other transport errors may be transient
+		return idempotent && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		// FTC rejects throttled requests before processing them
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// retryWait - Returns the Retry-After delay if FTC sent one, jittered exponential backoff otherwise
+func (c *Client) retryWait(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if wait, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return min(wait, c.MaxRetryWait)
+		}
+	}
+
+	backoff := min(retryBaseWait<<attempt, c.MaxRetryWait)
+	if backoff <= 0 {
+		return 0
+	}
+
+	// Equal jitter keeps at least half the backoff while spreading parallel callers apart
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter - Parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// rewindBody - Resets the request body so the request can be sent again
+func rewindBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if req.GetBody == nil {
+		return fmt.Errorf("cannot replay %s %s: request body is not rewindable", req.Method, req.URL.Path)
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return err
 	}
+	req.Body = body
 
-	return body, err
+	return nil
 }
@@ -0,0 +1,182 @@
+package ftc_client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// failServer - Fails the first requests after login with a given status
+type failServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	failures   int
+	status     int
+	retryAfter string
+	attempts   int
+}
+
+func newFailServer(t *testing.T) *failServer {
+	t.Helper()
+	fs := &failServer{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/login" {
+			fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+			return
+		}
+		fs.mu.Lock()
+		defer fs.mu.Unlock()
+		fs.attempts++
+		if fs.failures > 0 {
+			fs.failures--
+			if fs.retryAfter != "" {
+				w.Header().Set("Retry-After", fs.retryAfter)
+			}
+			w.WriteHeader(fs.status)
+			return
+		}
+		fmt.Fprint(w, `{"id": "app-1", "name": "app"}`)
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+// failNext - Makes the next n requests fail with status
+func (fs *failServer) failNext(n, status int, retryAfter string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.failures, fs.status, fs.retryAfter, fs.attempts = n, status, retryAfter, 0
+}
+
+func (fs *failServer) attemptCount() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.attempts
+}
+
+func newRetryClient(t *testing.T, fs *failServer, maxRetries int, maxRetryWait time.Duration) *ftc_client.Client {
+	t.Helper()
+	host, clientID, clientSecret := fs.URL, "client", "secret"
+	client, err := ftc_client.NewClient(&host, &clientID, &clientSecret, ftc_client.WithRetry(maxRetries, maxRetryWait))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		status   int
+		create   bool
+		attempts int
+		wantErr  bool
+	}{
+		{name: "GET retried on 503", failures: 3, status: http.StatusServiceUnavailable, attempts: 4},
+		{name: "GET retried on 502", failures: 1, status: http.StatusBadGateway, attempts: 2},
+		{name: "GET retried on 504", failures: 1, status: http.StatusGatewayTimeout, attempts: 2},
+		{name: "GET gives up after max retries", failures: 4, status: http.StatusServiceUnavailable, attempts: 4, wantErr: true},
+		{name: "GET not retried on 500", failures: 1, status: http.StatusInternalServerError, attempts: 1, wantErr: true},
+		{name: "POST retried on 429", failures: 2, status: http.StatusTooManyRequests, create: true, attempts: 3},
+		{name: "POST not retried on 503", failures: 1, status: http.StatusServiceUnavailable, create: true, attempts: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newFailServer(t)
+			client := newRetryClient(t, fs, 3, time.Millisecond)
+
+			fs.failNext(tt.failures, tt.status, "")
+			var err error
+			if tt.create {
+				_, err = client.CreateApplication(map[string]string{"name": "app"})
+			} else {
+				_, err = client.GetApplication("app-1")
+			}
+
+			if got := fs.attemptCount(); got != tt.attempts {
+				t.Errorf("sent %d attempts, want %d", got, tt.attempts)
+			}
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+				return
+			}
+			var apiErr *ftc_client.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Fatalf("got %v, want an APIError with status %d", err, tt.status)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name         string
+		retryAfter   string
+		maxRetryWait time.Duration
+		minWait      time.Duration
+		maxWait      time.Duration
+	}{
+		{name: "honoured", retryAfter: "1", maxRetryWait: 5 * time.Second, minWait: time.Second, maxWait: 3 * time.Second},
+		{name: "capped by max retry wait", retryAfter: "120", maxRetryWait: 50 * time.Millisecond, minWait: 50 * time.Millisecond, maxWait: 2 * time.Second},
+		{name: "HTTP date in the past", retryAfter: "Mon, 02 Jan 2006 15:04:05 GMT", maxRetryWait: 5 * time.Second, maxWait: 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newFailServer(t)
+			client := newRetryClient(t, fs, 1, tt.maxRetryWait)
+
+			fs.failNext(1, http.StatusTooManyRequests, tt.retryAfter)
+			start := time.Now()
+			if _, err := client.GetApplication("app-1"); err != nil {
+				t.Fatalf("GetApplication: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minWait || elapsed > tt.maxWait {
+				t.Errorf("retried after %s, want between %s and %s", elapsed, tt.minWait, tt.maxWait)
+			}
+		})
+	}
+}
+
+func TestRetryStopsAtDeadline(t *testing.T) {
+	fs := newFailServer(t)
+	client := newRetryClient(t, fs, 3, time.Minute)
+
+	fs.failNext(1, http.StatusTooManyRequests, "60")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetApplicationWithContext(ctx, "app-1")
+	if !ftc_client.IsRateLimited(err) {
+		t.Fatalf("got %v, want the 429 instead of waiting past the deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("returned after %s, want right away", elapsed)
+	}
+}
+
+func TestRetryWaitCancelled(t *testing.T) {
+	fs := newFailServer(t)
+	client := newRetryClient(t, fs, 3, time.Minute)
+
+	fs.failNext(1, http.StatusServiceUnavailable, "30")
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	_, err := client.GetApplicationWithContext(ctx, "app-1")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if got := fs.attemptCount(); got != 1 {
+		t.Errorf("sent %d attempts, want 1", got)
+	}
+}
@@ -0,0 +1,104 @@
+package ftc_client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError - Error returned for a request FTC answered with a 4xx or 5xx status
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	// Code and Message are decoded from FTC's JSON error body when present
+	Code      string
+	Message   string
+	RequestID string
+	Body      string
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s: status: %d", e.Method, e.Path, e.StatusCode)
+	if e.Code != "" {
+		fmt.Fprintf(&b, ", code: %s", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ", message: %s", e.Message)
+	} else if e.Body != "" {
+		fmt.Fprintf(&b, ", body: %s", e.Body)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, ", request id: %s", e.RequestID)
+	}
+	return b.String()
+}
+
+// newAPIError - Builds an APIError from a failed response
+func newAPIError(req *http.Request, res *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: res.StatusCode,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		RequestID:  res.Header.Get("X-Request-Id"),
+		Body:       string(body),
+	}
+
+	var payload map[string]interface{}
+	if json.Unmarshal(body, &payload) != nil {
+		return e
+	}
+
+	for _, key := range []string{"error_code", "code"} {
+		if v, ok := payload[key]; ok && v != nil {
+			e.Code = fmt.Sprint(v)
+			break
+		}
+	}
+	for _, key := range []string{"message", "error_message", "detail", "error"} {
+		if v, ok := payload[key].(string); ok && v != "" {
+			e.Message = v
+			break
+		}
+	}
+	if e.RequestID == "" {
+		if v, ok := payload["request_id"].(string); ok {
+			e.RequestID = v
+		}
+	}
+
+	return e
+}
+
+// statusCode - Returns the HTTP status of an APIError anywhere in err's chain, 0 otherwise
+func statusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound - Reports whether err is an FTC 404 response
+func IsNotFound(err error) bool {
+	return statusCode(err) == http.StatusNotFound
+}
+
+// IsConflict - Reports whether err is an FTC 409 response
+func IsConflict(err error) bool {
+	return statusCode(err) == http.StatusConflict
+}
+
+// IsRateLimited - Reports whether err is an FTC 429 response
+func IsRateLimited(err error) bool {
+	return statusCode(err) == http.StatusTooManyRequests
+}
+
+// IsUnauthorized - Reports whether err is an FTC 401 or 403 response
+func IsUnauthorized(err error) bool {
+	code := statusCode(err)
+	return code == http.StatusUnauthorized || code == http.StatusForbidden
+}
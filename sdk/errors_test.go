@@ -0,0 +1,144 @@
+package ftc_client_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// errorClient - Signs in to a server answering every other request with status, headers and body
+func errorClient(t *testing.T, status int, header http.Header, body string) *ftc_client.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/login" {
+			fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+			return
+		}
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	host, clientID, clientSecret := srv.URL, "client", "secret"
+	client, err := ftc_client.NewClient(&host, &clientID, &clientSecret, ftc_client.WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+		body   string
+		want   ftc_client.APIError
+		text   string
+	}{
+		{
+			name:   "JSON error body",
+			status: http.StatusConflict,
+			header: http.Header{"X-Request-Id": {"req-1"}},
+			body:   `{"error_code": 1042, "message": "name already in use"}`,
+			want:   ftc_client.APIError{StatusCode: 409, Code: "1042", Message: "name already in use", RequestID: "req-1"},
+			text:   "GET /api/v1/application/app-1: status: 409, code: 1042, message: name already in use, request id: req-1",
+		},
+		{
+			name:   "request ID in the body",
+			status: http.StatusBadRequest,
+			body:   `{"code": "invalid", "detail": "bad realm", "request_id": "req-2"}`,
+			want:   ftc_client.APIError{StatusCode: 400, Code: "invalid", Message: "bad realm", RequestID: "req-2"},
+			text:   "GET /api/v1/application/app-1: status: 400, code: invalid, message: bad realm, request id: req-2",
+		},
+		{
+			name:   "plain text body",
+			status: http.StatusInternalServerError,
+			body:   "internal error",
+			want:   ftc_client.APIError{StatusCode: 500},
+			text:   "GET /api/v1/application/app-1: status: 500, body: internal error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := errorClient(t, tt.status, tt.header, tt.body)
+
+			_, err := client.GetApplication("app-1")
+			var apiErr *ftc_client.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("got %v, want an APIError", err)
+			}
+			if apiErr.StatusCode != tt.want.StatusCode || apiErr.Code != tt.want.Code ||
+				apiErr.Message != tt.want.Message || apiErr.RequestID != tt.want.RequestID {
+				t.Errorf("APIError = %+v, want %+v", *apiErr, tt.want)
+			}
+			if apiErr.Method != http.MethodGet || apiErr.Path != "/api/v1/application/app-1" {
+				t.Errorf("APIError request = %s %s, want GET /api/v1/application/app-1", apiErr.Method, apiErr.Path)
+			}
+			if apiErr.Body != tt.body {
+				t.Errorf("APIError body = %q, want %q", apiErr.Body, tt.body)
+			}
+			if err.Error() != tt.text {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.text)
+			}
+		})
+	}
+}
+
+func TestStatusHelpers(t *testing.T) {
+	tests := []struct {
+		status int
+		is     func(error) bool
+	}{
+		{status: http.StatusNotFound, is: ftc_client.IsNotFound},
+		{status: http.StatusConflict, is: ftc_client.IsConflict},
+		{status: http.StatusTooManyRequests, is: ftc_client.IsRateLimited},
+		{status: http.StatusUnauthorized, is: ftc_client.IsUnauthorized},
+		{status: http.StatusForbidden, is: ftc_client.IsUnauthorized},
+	}
+	helpers := []func(error) bool{ftc_client.IsNotFound, ftc_client.IsConflict, ftc_client.IsRateLimited, ftc_client.IsUnauthorized}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			err := error(&ftc_client.APIError{StatusCode: tt.status})
+			// helpers look through wrapping
+			wrapped := fmt.Errorf("reading application: %w", err)
+			if !tt.is(err) || !tt.is(wrapped) {
+				t.Errorf("helper does not match status %d", tt.status)
+			}
+			matches := 0
+			for _, is := range helpers {
+				if is(err) {
+					matches++
+				}
+			}
+			if matches != 1 {
+				t.Errorf("status %d matched %d helpers, want 1", tt.status, matches)
+			}
+		})
+	}
+
+	if ftc_client.IsNotFound(errors.New("status: 404")) || ftc_client.IsNotFound(nil) {
+		t.Error("IsNotFound matched an error that is not an APIError")
+	}
+}
+
+func TestSignInAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error": "invalid_client"}`)
+	}))
+	defer srv.Close()
+
+	host, clientID, clientSecret := srv.URL, "client", "wrong"
+	_, err := ftc_client.NewClient(&host, &clientID, &clientSecret, ftc_client.WithRetry(0, 0))
+	if !ftc_client.IsUnauthorized(err) {
+		t.Fatalf("NewClient with a wrong secret: got %v, want a 401 APIError", err)
+	}
+}
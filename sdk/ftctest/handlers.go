@@ -0,0 +1,1241 @@
+package ftctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"time"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// hexColor - Color format accepted for branding colors
+var hexColor = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// oidcGrantTypes - Grant types accepted for OIDC applications
+var oidcGrantTypes = []string{"authorization_code", "refresh_token", "client_credentials"}
+
+// nameIDFormats - NameID formats accepted for SAML applications
+var nameIDFormats = []string{
+	"urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified",
+	"urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress",
+	"urn:oasis:names:tc:SAML:1.1:nameid-format:X509SubjectName",
+	"urn:oasis:names:tc:SAML:1.1:nameid-format:WindowsDomainQualifiedName",
+	"urn:oasis:names:tc:SAML:2.0:nameid-format:kerberos",
+	"urn:oasis:names:tc:SAML:2.0:nameid-format:entity",
+	"urn:oasis:names:tc:SAML:2.0:nameid-format:persistent",
+	"urn:oasis:names:tc:SAML:2.0:nameid-format:transient",
+}
+
+// payload - Decoded JSON object, keys set to null are present with a nil value
+type payload map[string]json.RawMessage
+
+// str - Returns the string stored under key, present is false when the key was not sent
+func (p payload) str(key string) (value string, present bool, herr *httpError) {
+	raw, ok := p[key]
+	if !ok {
+		return "", false, nil
+	}
+	if string(raw) == "null" {
+		return "", true, nil
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", true, badRequest("%s must be a string", key)
+	}
+	return value, true, nil
+}
+
+// num - Returns the integer stored under key
+func (p payload) num(key string) (value int, present bool, herr *httpError) {
+	raw, ok := p[key]
+	if !ok || string(raw) == "null" {
+		return 0, ok, nil
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return 0, true, badRequest("%s must be an integer", key)
+	}
+	return value, true, nil
+}
+
+// boolean - Returns the bool stored under key
+func (p payload) boolean(key string) (value bool, present bool, herr *httpError) {
+	raw, ok := p[key]
+	if !ok || string(raw) == "null" {
+		return false, ok, nil
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return false, true, badRequest("%s must be a boolean", key)
+	}
+	return value, true, nil
+}
+
+// object - Returns the nested object stored under key
+func (p payload) object(key string) (payload, *httpError) {
+	raw, ok := p[key]
+	if !ok || string(raw) == "null" {
+		return nil, nil
+	}
+	obj := payload{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, badRequest("%s must be an object", key)
+	}
+	return obj, nil
+}
+
+// value - Returns the value stored under key decoded into generic JSON types
+func (p payload) value(key string) (interface{}, bool) {
+	raw, ok := p[key]
+	if !ok {
+		return nil, false
+	}
+	var v interface{}
+	_ = json.Unmarshal(raw, &v)
+	return v, true
+}
+
+// strs - Returns the string list stored under key
+func (p payload) strs(key string) (value []string, present bool, herr *httpError) {
+	raw, ok := p[key]
+	if !ok || string(raw) == "null" {
+		return nil, ok, nil
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, true, badRequest("%s must be a list of strings", key)
+	}
+	return value, true, nil
+}
+
+// setStr - Copies a string field from the payload into dst when it was sent
+func setStr(p payload, key string, dst *string) *httpError {
+	v, ok, herr := p.str(key)
+	if herr != nil {
+		return herr
+	}
+	if ok {
+		*dst = v
+	}
+	return nil
+}
+
+func decodePayload(r *http.Request) (payload, *httpError) {
+	p := payload{}
+	if herr := decode(r, &p); herr != nil {
+		return nil, herr
+	}
+	return p, nil
+}
+
+// requireName - Validates the name field of a create request
+func requireName(p payload) (string, *httpError) {
+	name, _, herr := p.str("name")
+	if herr != nil {
+		return "", herr
+	}
+	if name == "" {
+		return "", badRequest("name is required")
+	}
+	return name, nil
+}
+
+// requireRealm - Validates the realm_id field of a create request
+func (s *Server) requireRealm(p payload) (string, *httpError) {
+	realmID, _, herr := p.str("realm_id")
+	if herr != nil {
+		return "", herr
+	}
+	if realmID == "" {
+		return "", badRequest("realm_id is required")
+	}
+	if _, ok := s.realms[realmID]; !ok {
+		return "", badRequest("realm %s does not exist", realmID)
+	}
+	return realmID, nil
+}
+
+// Realms
+
+func (s *Server) listRealms(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	realms := sortedValues(s.realms)
+	if name := r.URL.Query().Get("name"); name != "" {
+		filtered := []ftc_client.Realm{}
+		for _, realm := range realms {
+			if realm.Name == name {
+				filtered = append(filtered, realm)
+			}
+		}
+		realms = filtered
+	}
+	page, herr := paginate(r, realms)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	return http.StatusOK, page, nil
+}
+
+func (s *Server) createRealm(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	name, herr := requireName(p)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	for _, realm := range s.realms {
+		if realm.Name == name {
+			return 0, nil, conflict("realm %s already exists", name)
+		}
+	}
+	realm := &ftc_client.Realm{ID: newID(), Name: name}
+	s.realms[realm.ID] = realm
+	return http.StatusCreated, realm, nil
+}
+
+func (s *Server) getRealm(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	realm, ok := s.realms[ids[0]]
+	if !ok {
+		return 0, nil, notFound("realm", ids[0])
+	}
+	return http.StatusOK, realm, nil
+}
+
+func (s *Server) updateRealm(r *http.Request, ids []string) (int, interface{}, *httpError) {
+	realm, ok := s.realms[ids[0]]
+	if !ok {
+		return 0, nil, notFound("realm", ids[0])
+	}
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	name, herr := requireName(p)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	for _, other := range s.realms {
+		if other.Name == name && other.ID != realm.ID {
+			return 0, nil, conflict("realm %s already exists", name)
+		}
+	}
+	realm.Name = name
+	return http.StatusOK, realm, nil
+}
+
+func (s *Server) deleteRealm(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	if _, ok := s.realms[ids[0]]; !ok {
+		return 0, nil, notFound("realm", ids[0])
+	}
+	for _, app := range s.apps {
+		if app.RealmID == ids[0] {
+			return 0, nil, conflict("realm %s still contains application %s", ids[0], app.ID)
+		}
+	}
+	for _, us := range s.usersources {
+		if us.RealmID == ids[0] {
+			return 0, nil, conflict("realm %s still contains user source %s", ids[0], us.ID)
+		}
+	}
+	for _, branding := range s.brandings {
+		if branding.RealmID == ids[0] {
+			return 0, nil, conflict("realm %s still contains branding %s", ids[0], branding.ID)
+		}
+	}
+	for _, cert := range s.certs {
+		if cert.RealmID == ids[0] {
+			return 0, nil, conflict("realm %s still contains signing certificate %s", ids[0], cert.ID)
+		}
+	}
+	delete(s.realms, ids[0])
+	return http.StatusNoContent, nil, nil
+}
+
+// Applications
+
+// appView - Returns the application with its user source names resolved
+func (s *Server) appView(app *ftc_client.Application) ftc_client.Application {
+	view := *app
+	if app.OidcParams != nil {
+		params := *app.OidcParams
+		params.ClientSecret = ""
+		view.OidcParams = &params
+	}
+	view.UserSources = []ftc_client.UserSourceElement{}
+	for _, element := range app.UserSources {
+		if us, ok := s.usersources[element.ID]; ok {
+			view.UserSources = append(view.UserSources, ftc_client.UserSourceElement{
+				ID: us.ID, Name: us.Name, Type: us.Type, Prefix: us.Prefix,
+			})
+		}
+	}
+	return view
+}
+
+// applyApplication - Copies the fields of a create or update request onto app
+func (s *Server) applyApplication(app *ftc_client.Application, p payload) *httpError {
+	if herr := setStr(p, "name", &app.Name); herr != nil {
+		return herr
+	}
+	if app.Name == "" {
+		return badRequest("name is required")
+	}
+	ttl, ok, herr := p.num("ttl")
+	if herr != nil {
+		return herr
+	}
+	if ok {
+		if ttl < 0 {
+			return badRequest("ttl must not be negative")
+		}
+		app.TTL = ttl
+	}
+	if app.TTL == 0 {
+		app.TTL = 3600
+	}
+	if herr := setStr(p, "branding_id", &app.BrandingID); herr != nil {
+		return herr
+	}
+	if app.BrandingID != "" {
+		branding, ok := s.brandings[app.BrandingID]
+		if !ok {
+			return badRequest("branding %s does not exist", app.BrandingID)
+		}
+		if branding.RealmID != app.RealmID {
+			return badRequest("branding %s belongs to another realm", app.BrandingID)
+		}
+	}
+	if v, ok := p.value("attr_mapping"); ok {
+		if _, isMap := v.(map[string]interface{}); v != nil && !isMap {
+			return badRequest("attr_mapping must be an object")
+		}
+		app.AttrMapping = v
+	}
+
+	if app.Type == 2 {
+		if p["saml_params"] != nil && string(p["saml_params"]) != "null" {
+			return badRequest("saml_params is not supported by OIDC applications")
+		}
+		return applyOidcParams(app, p)
+	}
+	if p["oidc_params"] != nil && string(p["oidc_params"]) != "null" {
+		return badRequest("oidc_params is not supported by SAML applications")
+	}
+	saml, herr := p.object("saml_params")
+	if herr != nil {
+		return herr
+	}
+	if saml != nil {
+		for key, dst := range map[string]*string{
+			"signing_cert_id": &app.SigningCertID,
+			"entity_id":       &app.SpEntityID,
+			"acs_url":         &app.SpAcsUrl,
+			"slo_url":         &app.SpSloUrl,
+			"name_id":         &app.SpNameID,
+			"signing_cert":    &app.SpSigningCert,
+		} {
+			if herr := setStr(saml, key, dst); herr != nil {
+				return herr
+			}
+		}
+	}
+	if app.SpNameID != "" && !slices.Contains(nameIDFormats, app.SpNameID) {
+		return badRequest("saml_params.name_id %q is not a supported NameID format", app.SpNameID)
+	}
+	if app.SpSigningCert != "" {
+		if block, _ := pem.Decode([]byte(app.SpSigningCert)); block == nil || block.Type != "CERTIFICATE" {
+			return badRequest("saml_params.signing_cert must be a PEM encoded certificate")
+		} else if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return badRequest("saml_params.signing_cert is invalid: %s", err)
+		}
+	}
+	if app.SigningCertID != "" {
+		cert, ok := s.certs[app.SigningCertID]
+		if !ok {
+			return badRequest("signing certificate %s does not exist", app.SigningCertID)
+		}
+		if cert.RealmID != app.RealmID {
+			return badRequest("signing certificate %s belongs to another realm", app.SigningCertID)
+		}
+	}
+	return nil
+}
+
+// applyOidcParams - Copies the oidc_params of a request onto an OIDC application
+func applyOidcParams(app *ftc_client.Application, p payload) *httpError {
+	oidc, herr := p.object("oidc_params")
+	if herr != nil {
+		return herr
+	}
+	params := *app.OidcParams
+	if oidc != nil {
+		for key, dst := range map[string]*[]string{
+			"redirect_uris": &params.RedirectURIs,
+			"scopes":        &params.Scopes,
+			"grant_types":   &params.GrantTypes,
+		} {
+			v, ok, herr := oidc.strs(key)
+			if herr != nil {
+				return herr
+			}
+			if ok {
+				*dst = v
+			}
+		}
+		for key, dst := range map[string]*int{
+			"access_token_lifetime":  &params.AccessTokenLifetime,
+			"id_token_lifetime":      &params.IDTokenLifetime,
+			"refresh_token_lifetime": &params.RefreshTokenLifetime,
+		} {
+			v, ok, herr := oidc.num(key)
+			if herr != nil {
+				return herr
+			}
+			if ok {
+				*dst = v
+			}
+		}
+	}
+	if len(params.Scopes) == 0 {
+		params.Scopes = []string{"openid"}
+	}
+	if len(params.GrantTypes) == 0 {
+		params.GrantTypes = []string{"authorization_code"}
+	}
+	if !slices.Contains(params.Scopes, "openid") {
+		return badRequest("oidc_params.scopes must include openid")
+	}
+	for _, grant := range params.GrantTypes {
+		if !slices.Contains(oidcGrantTypes, grant) {
+			return badRequest("oidc_params.grant_types: unsupported grant type %q", grant)
+		}
+	}
+	if slices.Contains(params.GrantTypes, "authorization_code") && len(params.RedirectURIs) == 0 {
+		return badRequest("oidc_params.redirect_uris is required for the authorization_code grant")
+	}
+	for _, uri := range params.RedirectURIs {
+		u, err := url.Parse(uri)
+		if err != nil || u.Host == "" || u.Fragment != "" || (u.Scheme != "https" && !(u.Scheme == "http" && u.Hostname() == "localhost")) {
+			return badRequest("oidc_params.redirect_uris: %q must be an https URL without fragment", uri)
+		}
+	}
+	for key, lifetime := range map[string]*int{
+		"access_token_lifetime":  &params.AccessTokenLifetime,
+		"id_token_lifetime":      &params.IDTokenLifetime,
+		"refresh_token_lifetime": &params.RefreshTokenLifetime,
+	} {
+		if *lifetime == 0 {
+			*lifetime = 3600
+			if key == "refresh_token_lifetime" {
+				*lifetime = 86400
+			}
+		}
+		if *lifetime < 60 || *lifetime > 2592000 {
+			return badRequest("oidc_params.%s must be between 60 and 2592000 seconds", key)
+		}
+	}
+	app.OidcParams = &params
+	return nil
+}
+
+func (s *Server) listApplications(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	apps := []ftc_client.Application{}
+	for _, app := range sortedValues(s.apps) {
+		apps = append(apps, s.appView(&app))
+	}
+	page, herr := paginate(r, apps)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	return http.StatusOK, page, nil
+}
+
+func (s *Server) createApplication(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	realmID, herr := s.requireRealm(p)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	appType, ok, herr := p.num("type")
+	if herr != nil {
+		return 0, nil, herr
+	}
+	if !ok {
+		appType = 1
+	}
+	prefix := newPrefix()
+	app := &ftc_client.Application{
+		ID:      newID(),
+		RealmID: realmID,
+		Type:    appType,
+		Prefix:  prefix,
+	}
+	switch appType {
+	case 1:
+		// IdP endpoints FTC generates for every SAML application
+		app.EntityID = fmt.Sprintf("%s/saml-idp/%s/metadata/", s.URL, prefix)
+		app.SsoUrl = fmt.Sprintf("%s/saml-idp/%s/login/", s.URL, prefix)
+		app.SloUrl = fmt.Sprintf("%s/saml-idp/%s/logout/", s.URL, prefix)
+	case 2:
+		// OP issuer and client credentials FTC generates for every OIDC application
+		issuer := fmt.Sprintf("%s/oidc/%s", s.URL, prefix)
+		app.OidcParams = &ftc_client.OidcAppParams{
+			ClientID:     newID(),
+			ClientSecret: newPrefix() + newPrefix() + newPrefix() + newPrefix(),
+			Issuer:       issuer,
+			DiscoveryURL: issuer + "/.well-known/openid-configuration",
+		}
+	default:
+		return 0, nil, badRequest("type must be 1 (SAML) or 2 (OIDC)")
+	}
+	if herr := s.applyApplication(app, p); herr != nil {
+		return 0, nil, herr
+	}
+	for _, other := range s.apps {
+		if other.RealmID == app.RealmID && other.Name == app.Name {
+			return 0, nil, conflict("application %s already exists in realm %s", app.Name, app.RealmID)
+		}
+	}
+	s.apps[app.ID] = app
+	view := s.appView(app)
+	if app.OidcParams != nil {
+		// the client secret is only ever returned here
+		view.OidcParams.ClientSecret = app.OidcParams.ClientSecret
+	}
+	return http.StatusCreated, view, nil
+}
+
+func (s *Server) getApplication(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	app, ok := s.apps[ids[0]]
+	if !ok {
+		return 0, nil, notFound("application", ids[0])
+	}
+	return http.StatusOK, s.appView(app), nil
+}
+
+func (s *Server) updateApplication(r *http.Request, ids []string) (int, interface{}, *httpError) {
+	app, ok := s.apps[ids[0]]
+	if !ok {
+		return 0, nil, notFound("application", ids[0])
+	}
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	if appType, ok, herr := p.num("type"); herr != nil {
+		return 0, nil, herr
+	} else if ok && appType != app.Type {
+		return 0, nil, badRequest("type cannot be changed")
+	}
+	updated := *app
+	if herr := s.applyApplication(&updated, p); herr != nil {
+		return 0, nil, herr
+	}
+	*app = updated
+	return http.StatusOK, s.appView(app), nil
+}
+
+func (s *Server) deleteApplication(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	if _, ok := s.apps[ids[0]]; !ok {
+		return 0, nil, notFound("application", ids[0])
+	}
+	delete(s.apps, ids[0])
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) updateApplicationUserSources(r *http.Request, ids []string) (int, interface{}, *httpError) {
+	app, ok := s.apps[ids[0]]
+	if !ok {
+		return 0, nil, notFound("application", ids[0])
+	}
+	list := ftc_client.UserSourceList{}
+	if herr := decode(r, &list); herr != nil {
+		return 0, nil, herr
+	}
+	if list.UserSourceIDs == nil {
+		return 0, nil, badRequest("user_source_ids is required")
+	}
+	elements := []ftc_client.UserSourceElement{}
+	mappings := []ftc_client.AppUserMapping{}
+	for _, id := range list.UserSourceIDs {
+		us, ok := s.usersources[id]
+		if !ok {
+			return 0, nil, badRequest("user source %s does not exist", id)
+		}
+		if us.RealmID != app.RealmID {
+			return 0, nil, badRequest("user source %s belongs to another realm", id)
+		}
+		elements = append(elements, ftc_client.UserSourceElement{ID: id})
+		mappings = append(mappings, ftc_client.AppUserMapping{ApplicationID: app.ID, UserSourceID: id})
+	}
+	app.UserSources = elements
+	return http.StatusOK, mappings, nil
+}
+
+// User sources
+
+// usView - Returns the user source with its domains resolved and the client secret withheld,
+// or as ClientSecretView returns it
+func (s *Server) usView(us *ftc_client.UserSource) ftc_client.UserSource {
+	view := *us
+	view.ClientSecret = ""
+	if s.ClientSecretView != nil {
+		view.ClientSecret = s.ClientSecretView(us.ClientSecret)
+	}
+	view.Domains = []ftc_client.DomainElement{}
+	for _, domain := range sortedValues(s.domains) {
+		if domain.UserSourceID == us.ID {
+			view.Domains = append(view.Domains, ftc_client.DomainElement{ID: domain.ID, Name: domain.Name})
+		}
+	}
+	return view
+}
+
+// applyUserSource - Copies the fields of a create or update request onto us
+func (s *Server) applyUserSource(us *ftc_client.UserSource, p payload) *httpError {
+	if herr := setStr(p, "name", &us.Name); herr != nil {
+		return herr
+	}
+	if us.Name == "" {
+		return badRequest("name is required")
+	}
+	for key, dst := range map[string]*string{
+		"username_assertion": &us.UsernameAssertion,
+		"login_hint":         &us.LoginHint,
+	} {
+		if herr := setStr(p, key, dst); herr != nil {
+			return herr
+		}
+	}
+	if v, ok := p.value("attr_mapping"); ok {
+		if _, isMap := v.(map[string]interface{}); v != nil && !isMap {
+			return badRequest("attr_mapping must be an object")
+		}
+		us.AttrMapping = v
+	}
+
+	switch us.Type {
+	case 1:
+		saml, herr := p.object("saml_params")
+		if herr != nil {
+			return herr
+		}
+		if p["oidc_params"] != nil && string(p["oidc_params"]) != "null" {
+			return badRequest("oidc_params is not supported by SAML user sources")
+		}
+		if saml != nil {
+			for key, dst := range map[string]*string{
+				"entity_id":    &us.EntityID,
+				"login_url":    &us.LoginUrl,
+				"logout_url":   &us.LogoutUrl,
+				"signing_cert": &us.SigningCert,
+			} {
+				if herr := setStr(saml, key, dst); herr != nil {
+					return herr
+				}
+			}
+			for key, dst := range map[string]*bool{
+				"post_binding":    &us.PostBinding,
+				"include_subject": &us.IncludeSubject,
+			} {
+				v, ok, herr := saml.boolean(key)
+				if herr != nil {
+					return herr
+				}
+				if ok {
+					*dst = v
+				}
+			}
+		}
+		if us.EntityID == "" || us.LoginUrl == "" {
+			return badRequest("saml_params.entity_id and saml_params.login_url are required")
+		}
+	case 2:
+		oidc, herr := p.object("oidc_params")
+		if herr != nil {
+			return herr
+		}
+		if p["saml_params"] != nil && string(p["saml_params"]) != "null" {
+			return badRequest("saml_params is not supported by OIDC user sources")
+		}
+		if oidc != nil {
+			for key, dst := range map[string]*string{
+				"auth_uri":      &us.AuthUri,
+				"token_uri":     &us.TokenUri,
+				"userinfo_uri":  &us.UserInfoUri,
+				"logout_uri":    &us.LogoutUri,
+				"issuer":        &us.Issuer,
+				"client_id":     &us.ClientID,
+				"client_secret": &us.ClientSecret,
+			} {
+				if herr := setStr(oidc, key, dst); herr != nil {
+					return herr
+				}
+			}
+		}
+		if us.AuthUri == "" || us.TokenUri == "" || us.ClientID == "" {
+			return badRequest("oidc_params.auth_uri, oidc_params.token_uri and oidc_params.client_id are required")
+		}
+	}
+	return nil
+}
+
+func (s *Server) listUserSources(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	usersources := []ftc_client.UserSource{}
+	for _, us := range sortedValues(s.usersources) {
+		usersources = append(usersources, s.usView(&us))
+	}
+	page, herr := paginate(r, usersources)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	return http.StatusOK, page, nil
+}
+
+func (s *Server) createUserSource(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	realmID, herr := s.requireRealm(p)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	usType, _, herr := p.num("type")
+	if herr != nil {
+		return 0, nil, herr
+	}
+	if usType != 1 && usType != 2 {
+		return 0, nil, badRequest("type must be 1 (SAML) or 2 (OIDC)")
+	}
+	prefix := newPrefix()
+	us := &ftc_client.UserSource{
+		ID:                newID(),
+		RealmID:           realmID,
+		Type:              usType,
+		Prefix:            prefix,
+		UsernameAssertion: "username",
+		AttrMapping:       map[string]interface{}{},
+		FQDN:              s.Listener.Addr().String(),
+	}
+	if usType == 1 {
+		us.ProxySP = ftc_client.ProxySP{
+			Prefix:   prefix,
+			EntityID: fmt.Sprintf("%s/saml-sp/%s/metadata/", s.URL, prefix),
+			AcsUrl:   fmt.Sprintf("%s/saml-sp/%s/acs/", s.URL, prefix),
+			SloUrl:   fmt.Sprintf("%s/saml-sp/%s/sls/", s.URL, prefix),
+			SsoUrl:   fmt.Sprintf("%s/saml-sp/%s/login/", s.URL, prefix),
+		}
+	} else {
+		us.ProxySP = ftc_client.ProxySP{
+			Prefix:                prefix,
+			CallbackUrl:           fmt.Sprintf("%s/oidc-sp/%s/callback/", s.URL, prefix),
+			PostLogoutRedirectUrl: fmt.Sprintf("%s/oidc-sp/%s/logout/", s.URL, prefix),
+			OidcLoginUrl:          fmt.Sprintf("%s/oidc-sp/%s/login/", s.URL, prefix),
+		}
+	}
+	if herr := s.applyUserSource(us, p); herr != nil {
+		return 0, nil, herr
+	}
+	for _, other := range s.usersources {
+		if other.RealmID == us.RealmID && other.Name == us.Name {
+			return 0, nil, conflict("user source %s already exists in realm %s", us.Name, us.RealmID)
+		}
+	}
+	s.usersources[us.ID] = us
+	return http.StatusCreated, s.usView(us), nil
+}
+
+func (s *Server) getUserSource(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	us, ok := s.usersources[ids[0]]
+	if !ok {
+		return 0, nil, notFound("user source", ids[0])
+	}
+	return http.StatusOK, s.usView(us), nil
+}
+
+func (s *Server) updateUserSource(r *http.Request, ids []string) (int, interface{}, *httpError) {
+	us, ok := s.usersources[ids[0]]
+	if !ok {
+		return 0, nil, notFound("user source", ids[0])
+	}
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	updated := *us
+	if herr := s.applyUserSource(&updated, p); herr != nil {
+		return 0, nil, herr
+	}
+	*us = updated
+	return http.StatusOK, s.usView(us), nil
+}
+
+func (s *Server) deleteUserSource(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	if _, ok := s.usersources[ids[0]]; !ok {
+		return 0, nil, notFound("user source", ids[0])
+	}
+	for _, app := range s.apps {
+		for _, element := range app.UserSources {
+			if element.ID == ids[0] {
+				return 0, nil, conflict("user source %s is used by application %s", ids[0], app.ID)
+			}
+		}
+	}
+	for _, domain := range s.domains {
+		if domain.UserSourceID == ids[0] {
+			domain.UserSourceID = ""
+		}
+	}
+	delete(s.usersources, ids[0])
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) updateUserSourceDomains(r *http.Request, ids []string) (int, interface{}, *httpError) {
+	us, ok := s.usersources[ids[0]]
+	if !ok {
+		return 0, nil, notFound("user source", ids[0])
+	}
+	var list struct {
+		DomainIDs []string `json:"domain_ids"`
+	}
+	if herr := decode(r, &list); herr != nil {
+		return 0, nil, herr
+	}
+	if list.DomainIDs == nil {
+		return 0, nil, badRequest("domain_ids is required")
+	}
+	wanted := map[string]bool{}
+	for _, id := range list.DomainIDs {
+		domain, ok := s.domains[id]
+		if !ok {
+			return 0, nil, badRequest("domain %s does not exist", id)
+		}
+		if domain.RealmID != us.RealmID {
+			return 0, nil, badRequest("domain %s belongs to another realm", id)
+		}
+		wanted[id] = true
+	}
+	mappings := []ftc_client.UserSourceDomainMapping{}
+	for _, domain := range s.domains {
+		switch {
+		case wanted[domain.ID]:
+			domain.UserSourceID = us.ID
+			mappings = append(mappings, ftc_client.UserSourceDomainMapping{UserSourceID: us.ID, DomainID: domain.ID})
+		case domain.UserSourceID == us.ID:
+			domain.UserSourceID = ""
+		}
+	}
+	return http.StatusOK, mappings, nil
+}
+
+// Domains
+
+// applyDomain - Copies the fields of a create or update request onto domain
+func (s *Server) applyDomain(domain *ftc_client.Domain, p payload) *httpError {
+	if herr := setStr(p, "name", &domain.Name); herr != nil {
+		return herr
+	}
+	if domain.Name == "" {
+		return badRequest("name is required")
+	}
+	if herr := setStr(p, "user_source_id", &domain.UserSourceID); herr != nil {
+		return herr
+	}
+	if domain.UserSourceID != "" {
+		us, ok := s.usersources[domain.UserSourceID]
+		if !ok {
+			return badRequest("user source %s does not exist", domain.UserSourceID)
+		}
+		if us.RealmID != domain.RealmID {
+			return badRequest("user source %s belongs to another realm", domain.UserSourceID)
+		}
+	}
+	for _, other := range s.domains {
+		if other.Name == domain.Name && other.ID != domain.ID {
+			return conflict("domain %s already exists", domain.Name)
+		}
+	}
+	return nil
+}
+
+func (s *Server) createDomain(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	realmID, herr := s.requireRealm(p)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	domain := &ftc_client.Domain{ID: newID(), RealmID: realmID}
+	if herr := s.applyDomain(domain, p); herr != nil {
+		return 0, nil, herr
+	}
+	s.domains[domain.ID] = domain
+	return http.StatusCreated, domain, nil
+}
+
+func (s *Server) getDomain(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	domain, ok := s.domains[ids[0]]
+	if !ok {
+		return 0, nil, notFound("domain", ids[0])
+	}
+	return http.StatusOK, domain, nil
+}
+
+func (s *Server) updateDomain(r *http.Request, ids []string) (int, interface{}, *httpError) {
+	domain, ok := s.domains[ids[0]]
+	if !ok {
+		return 0, nil, notFound("domain", ids[0])
+	}
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	updated := *domain
+	if herr := s.applyDomain(&updated, p); herr != nil {
+		return 0, nil, herr
+	}
+	*domain = updated
+	return http.StatusOK, domain, nil
+}
+
+func (s *Server) deleteDomain(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	if _, ok := s.domains[ids[0]]; !ok {
+		return 0, nil, notFound("domain", ids[0])
+	}
+	delete(s.domains, ids[0])
+	return http.StatusNoContent, nil, nil
+}
+
+// Brandings
+
+// maxLogoSize - Largest decoded logo the server accepts
+const maxLogoSize = 512 * 1024
+
+// applyBranding - Copies the fields of a create or update request onto branding
+func (s *Server) applyBranding(branding *ftc_client.Branding, p payload) *httpError {
+	for key, dst := range map[string]*string{
+		"name":             &branding.Name,
+		"title":            &branding.Title,
+		"logo":             &branding.Logo,
+		"primary_color":    &branding.PrimaryColor,
+		"background_color": &branding.BackgroundColor,
+		"text_color":       &branding.TextColor,
+		"footer_text":      &branding.FooterText,
+	} {
+		if herr := setStr(p, key, dst); herr != nil {
+			return herr
+		}
+	}
+	if branding.Name == "" {
+		return badRequest("name is required")
+	}
+	for key, color := range map[string]string{
+		"primary_color":    branding.PrimaryColor,
+		"background_color": branding.BackgroundColor,
+		"text_color":       branding.TextColor,
+	} {
+		if color != "" && !hexColor.MatchString(color) {
+			return badRequest("%s must be a #RRGGBB color", key)
+		}
+	}
+	if branding.Logo != "" {
+		logo, err := base64.StdEncoding.DecodeString(branding.Logo)
+		if err != nil {
+			return badRequest("logo must be base64 encoded")
+		}
+		if len(logo) > maxLogoSize {
+			return badRequest("logo must not be larger than %d bytes", maxLogoSize)
+		}
+	}
+	for _, other := range s.brandings {
+		if other.RealmID == branding.RealmID && other.Name == branding.Name && other.ID != branding.ID {
+			return conflict("branding %s already exists in realm %s", branding.Name, branding.RealmID)
+		}
+	}
+	return nil
+}
+
+func (s *Server) listBrandings(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	page, herr := paginate(r, sortedValues(s.brandings))
+	if herr != nil {
+		return 0, nil, herr
+	}
+	return http.StatusOK, page, nil
+}
+
+func (s *Server) createBranding(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	realmID, herr := s.requireRealm(p)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	branding := &ftc_client.Branding{ID: newID(), RealmID: realmID}
+	if herr := s.applyBranding(branding, p); herr != nil {
+		return 0, nil, herr
+	}
+	s.brandings[branding.ID] = branding
+	return http.StatusCreated, branding, nil
+}
+
+func (s *Server) getBranding(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	branding, ok := s.brandings[ids[0]]
+	if !ok {
+		return 0, nil, notFound("branding", ids[0])
+	}
+	return http.StatusOK, branding, nil
+}
+
+func (s *Server) updateBranding(r *http.Request, ids []string) (int, interface{}, *httpError) {
+	branding, ok := s.brandings[ids[0]]
+	if !ok {
+		return 0, nil, notFound("branding", ids[0])
+	}
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	updated := *branding
+	if herr := s.applyBranding(&updated, p); herr != nil {
+		return 0, nil, herr
+	}
+	*branding = updated
+	return http.StatusOK, branding, nil
+}
+
+func (s *Server) deleteBranding(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	if _, ok := s.brandings[ids[0]]; !ok {
+		return 0, nil, notFound("branding", ids[0])
+	}
+	for _, app := range s.apps {
+		if app.BrandingID == ids[0] {
+			return 0, nil, conflict("branding %s is used by application %s", ids[0], app.ID)
+		}
+	}
+	delete(s.brandings, ids[0])
+	return http.StatusNoContent, nil, nil
+}
+
+// Signing certificates
+
+// parseUploadedCert - Decodes an uploaded PEM certificate and checks the key belongs to it
+func parseUploadedCert(certPEM, keyPEM string) (*x509.Certificate, *httpError) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, badRequest("certificate must be a PEM encoded certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, badRequest("certificate is invalid: %s", err)
+	}
+	block, _ = pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, badRequest("private_key is required with certificate")
+	}
+	var key interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, badRequest("private_key has unsupported PEM type %s", block.Type)
+	}
+	if err != nil {
+		return nil, badRequest("private_key is invalid: %s", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, badRequest("private_key is not a signing key")
+	}
+	public, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !public.Equal(signer.Public()) {
+		return nil, badRequest("private_key does not match certificate")
+	}
+	return cert, nil
+}
+
+// generateCert - Returns a self-signed RSA certificate like the ones FTC generates, valid for days
+func generateCert(commonName string, days int) (*x509.Certificate, *httpError) {
+	now := time.Now().UTC().Truncate(time.Second)
+	return generateCertValidity(commonName, now, now.AddDate(0, 0, days))
+}
+
+// generateCertValidity - Returns a self-signed RSA certificate valid from notBefore to notAfter
+func generateCertValidity(commonName string, notBefore, notAfter time.Time) (*x509.Certificate, *httpError) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, apiError{"internal", err.Error()}}
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, apiError{"internal", err.Error()}}
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, apiError{"internal", err.Error()}}
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, apiError{"internal", err.Error()}}
+	}
+	return cert, nil
+}
+
+func (s *Server) listSigningCerts(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	page, herr := paginate(r, sortedValues(s.certs))
+	if herr != nil {
+		return 0, nil, herr
+	}
+	return http.StatusOK, page, nil
+}
+
+func (s *Server) createSigningCert(r *http.Request, _ []string) (int, interface{}, *httpError) {
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	realmID, herr := s.requireRealm(p)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	name, herr := requireName(p)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	for _, other := range s.certs {
+		if other.RealmID == realmID && other.Name == name {
+			return 0, nil, conflict("signing certificate %s already exists in realm %s", name, realmID)
+		}
+	}
+
+	certPEM, _, herr := p.str("certificate")
+	if herr != nil {
+		return 0, nil, herr
+	}
+	keyPEM, _, herr := p.str("private_key")
+	if herr != nil {
+		return 0, nil, herr
+	}
+	commonName, _, herr := p.str("common_name")
+	if herr != nil {
+		return 0, nil, herr
+	}
+	days, daysSet, herr := p.num("validity_days")
+	if herr != nil {
+		return 0, nil, herr
+	}
+
+	var cert *x509.Certificate
+	switch {
+	case certPEM != "" && commonName != "":
+		return 0, nil, badRequest("certificate and common_name are mutually exclusive")
+	case certPEM != "":
+		cert, herr = parseUploadedCert(certPEM, keyPEM)
+	case commonName != "":
+		if !daysSet {
+			days = 365
+		}
+		if days < 1 || days > 3650 {
+			return 0, nil, badRequest("validity_days must be between 1 and 3650")
+		}
+		cert, herr = generateCert(commonName, days)
+	default:
+		return 0, nil, badRequest("certificate or common_name is required")
+	}
+	if herr != nil {
+		return 0, nil, herr
+	}
+
+	record := &ftc_client.SigningCertificate{
+		ID:      newID(),
+		Name:    name,
+		RealmID: realmID,
+	}
+	setCertificate(record, cert)
+	s.certs[record.ID] = record
+	return http.StatusCreated, record, nil
+}
+
+// setCertificate - Stores cert and the details FTC reports about it in record
+func setCertificate(record *ftc_client.SigningCertificate, cert *x509.Certificate) {
+	record.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	record.Subject = cert.Subject.String()
+	record.SerialNumber = cert.SerialNumber.Text(16)
+	record.NotBefore = cert.NotBefore.UTC().Format(time.RFC3339)
+	record.NotAfter = cert.NotAfter.UTC().Format(time.RFC3339)
+}
+
+func (s *Server) getSigningCert(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	cert, ok := s.certs[ids[0]]
+	if !ok {
+		return 0, nil, notFound("signing certificate", ids[0])
+	}
+	return http.StatusOK, cert, nil
+}
+
+func (s *Server) updateSigningCert(r *http.Request, ids []string) (int, interface{}, *httpError) {
+	cert, ok := s.certs[ids[0]]
+	if !ok {
+		return 0, nil, notFound("signing certificate", ids[0])
+	}
+	p, herr := decodePayload(r)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	for _, key := range []string{"certificate", "private_key", "common_name", "validity_days", "realm_id"} {
+		if _, ok := p[key]; ok {
+			return 0, nil, badRequest("%s cannot be changed, upload a new certificate", key)
+		}
+	}
+	name, herr := requireName(p)
+	if herr != nil {
+		return 0, nil, herr
+	}
+	for _, other := range s.certs {
+		if other.RealmID == cert.RealmID && other.Name == name && other.ID != cert.ID {
+			return 0, nil, conflict("signing certificate %s already exists in realm %s", name, cert.RealmID)
+		}
+	}
+	cert.Name = name
+	return http.StatusOK, cert, nil
+}
+
+func (s *Server) deleteSigningCert(_ *http.Request, ids []string) (int, interface{}, *httpError) {
+	if _, ok := s.certs[ids[0]]; !ok {
+		return 0, nil, notFound("signing certificate", ids[0])
+	}
+	for _, app := range s.apps {
+		if app.SigningCertID == ids[0] {
+			return 0, nil, conflict("signing certificate %s is used by application %s", ids[0], app.ID)
+		}
+	}
+	delete(s.certs, ids[0])
+	return http.StatusNoContent, nil, nil
+}
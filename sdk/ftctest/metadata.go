@@ -0,0 +1,103 @@
+package ftctest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+// idpMetadataTemplate - IdP metadata FTC publishes at each application entity ID
+const idpMetadataTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="%[1]s">
+  <md:IDPSSODescriptor WantAuthnRequestsSigned="false" protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo><ds:X509Data><ds:X509Certificate>%[4]s</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="%[3]s"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="%[2]s"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="%[2]s"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>
+`
+
+// oidcConfiguration - Serves the unauthenticated OpenID Provider configuration of the OIDC application with prefix
+func (s *Server) oidcConfiguration(w http.ResponseWriter, prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, app := range s.apps {
+		if app.Prefix != prefix || app.OidcParams == nil {
+			continue
+		}
+		issuer := app.OidcParams.Issuer
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                   issuer,
+			"authorization_endpoint":   issuer + "/authorize",
+			"token_endpoint":           issuer + "/token",
+			"userinfo_endpoint":        issuer + "/userinfo",
+			"end_session_endpoint":     issuer + "/logout",
+			"jwks_uri":                 issuer + "/jwks",
+			"scopes_supported":         []string{"openid", "profile", "email", "groups"},
+			"grant_types_supported":    oidcGrantTypes,
+			"response_types_supported": []string{"code"},
+		})
+		return
+	}
+	writeJSON(w, http.StatusNotFound, apiError{"not_found", "no OIDC application with prefix " + prefix})
+}
+
+// newSigningCert - Returns a self-signed certificate standing in for the FTC IdP signing cert
+func newSigningCert() (*x509.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "ftctest IdP signing"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// SigningCert - Returns the PEM encoded IdP signing certificate published in application metadata
+func (s *Server) SigningCert() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.signingCert.Raw}))
+}
+
+// idpMetadata - Serves the unauthenticated IdP metadata of the application with prefix
+func (s *Server) idpMetadata(w http.ResponseWriter, prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, app := range s.apps {
+		if app.Prefix != prefix {
+			continue
+		}
+		// an application with its own signing certificate publishes that one
+		der := s.signingCert.Raw
+		if cert, ok := s.certs[app.SigningCertID]; ok {
+			if block, _ := pem.Decode([]byte(cert.Certificate)); block != nil {
+				der = block.Bytes
+			}
+		}
+		w.Header().Set("Content-Type", "application/samlmetadata+xml")
+		fmt.Fprintf(w, idpMetadataTemplate, app.EntityID, app.SsoUrl, app.SloUrl, base64.StdEncoding.EncodeToString(der))
+		return
+	}
+	writeJSON(w, http.StatusNotFound, apiError{"not_found", "no application with prefix " + prefix})
+}
@@ -0,0 +1,490 @@
+// Package ftctest provides an in-memory FortiTokenCloud API for tests.
+//
+// The server speaks the same JSON as FTC for login, realms, brandings, signing
+// certificates, applications, user sources and domains, and publishes SAML IdP
+// metadata or the OpenID Provider configuration for each application, so both
+// the SDK and the provider can be exercised without a live tenant.
+// Acceptance tests point the provider at it through the usual environment
+// variables:
+//
+//	srv := ftctest.NewServer(t)
+//	defer srv.Close()
+//	t.Setenv("FTC_HOST", srv.URL)
+//	t.Setenv("FTC_CLIENTID", ftctest.ClientID)
+//	t.Setenv("FTC_CLIENTSECRET", ftctest.ClientSecret)
+package ftctest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// ClientID - API client ID accepted by the server
+const ClientID = "ftctest-client-id"
+
+// ClientSecret - API client secret accepted by the server
+const ClientSecret = "ftctest-client-secret"
+
+// DefaultRealm - Name of the realm every server starts with
+const DefaultRealm = "default"
+
+// Server - In-memory FTC API served over HTTP
+type Server struct {
+	*httptest.Server
+
+	// TokenTTL is reported as expires_in and enforced on every request
+	TokenTTL time.Duration
+
+	// ClientSecretView turns the stored client secret of a user source into the value
+	// returned by reads, which withhold it when nil
+	ClientSecretView func(secret string) string
+
+	mu          sync.Mutex
+	tokens      map[string]time.Time
+	realms      map[string]*ftc_client.Realm
+	brandings   map[string]*ftc_client.Branding
+	certs       map[string]*ftc_client.SigningCertificate
+	apps        map[string]*ftc_client.Application
+	usersources map[string]*ftc_client.UserSource
+	domains     map[string]*ftc_client.Domain
+	failures    []failure
+	signingCert *x509.Certificate
+	bodies      map[string]string
+}
+
+// failure - Response injected with FailNext
+type failure struct {
+	status     int
+	retryAfter string
+}
+
+// NewServer - Starts a server holding only the default realm, failing tb if it cannot
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+	signingCert, err := newSigningCert()
+	if err != nil {
+		tb.Fatalf("ftctest: generating the IdP signing certificate: %v", err)
+	}
+	s := &Server{
+		TokenTTL:    time.Hour,
+		tokens:      map[string]time.Time{},
+		realms:      map[string]*ftc_client.Realm{},
+		brandings:   map[string]*ftc_client.Branding{},
+		certs:       map[string]*ftc_client.SigningCertificate{},
+		apps:        map[string]*ftc_client.Application{},
+		usersources: map[string]*ftc_client.UserSource{},
+		domains:     map[string]*ftc_client.Domain{},
+		signingCert: signingCert,
+		bodies:      map[string]string{},
+	}
+
+	realm := &ftc_client.Realm{ID: newID(), Name: DefaultRealm}
+	s.realms[realm.ID] = realm
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ProviderConfig - Returns a provider block pointing at the server
+func (s *Server) ProviderConfig() string {
+	return fmt.Sprintf(`
+provider "fortitokencloud" {
+  host         = %q
+  clientid     = %q
+  clientsecret = %q
+  max_retries  = 0
+}
+`, s.URL, ClientID, ClientSecret)
+}
+
+// DefaultRealmID - Returns the ID of the default realm
+func (s *Server) DefaultRealmID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, realm := range s.realms {
+		if realm.Name == DefaultRealm {
+			return realm.ID
+		}
+	}
+	return ""
+}
+
+// ExpireTokens - Invalidates every issued access token, the next request gets a 401
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]time.Time{}
+}
+
+// ExpireSigningCertificate - Replaces a stored signing certificate with one of the same
+// subject that expired yesterday, as if time had passed
+func (s *Server) ExpireSigningCertificate(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.certs[id]
+	if !ok {
+		return fmt.Errorf("signing certificate %s does not exist", id)
+	}
+	block, _ := pem.Decode([]byte(record.Certificate))
+	if block == nil {
+		return fmt.Errorf("signing certificate %s is not PEM encoded", id)
+	}
+	current, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	cert, herr := generateCertValidity(current.Subject.CommonName, now.AddDate(0, 0, -30), now.AddDate(0, 0, -1))
+	if herr != nil {
+		return herr
+	}
+	setCertificate(record, cert)
+	return nil
+}
+
+// LastRequestBody - Returns the body of the last authenticated request sent with method
+// to path, as received on the wire
+func (s *Server) LastRequestBody(method, path string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bodies[method+" "+path]
+}
+
+// FailNext - Answers the next n authenticated requests with status, sending
+// retryAfter as Retry-After header when it is not empty
+func (s *Server) FailNext(n int, status int, retryAfter string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, failure{status: status, retryAfter: retryAfter})
+	}
+}
+
+// Application - Returns a copy of a stored application
+func (s *Server) Application(id string) (ftc_client.Application, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	app, ok := s.apps[id]
+	if !ok {
+		return ftc_client.Application{}, false
+	}
+	return *app, true
+}
+
+// UserSource - Returns a copy of a stored user source
+func (s *Server) UserSource(id string) (ftc_client.UserSource, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	us, ok := s.usersources[id]
+	if !ok {
+		return ftc_client.UserSource{}, false
+	}
+	return *us, true
+}
+
+// Branding - Returns a copy of a stored branding
+func (s *Server) Branding(id string) (ftc_client.Branding, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	branding, ok := s.brandings[id]
+	if !ok {
+		return ftc_client.Branding{}, false
+	}
+	return *branding, true
+}
+
+// SigningCertificate - Returns a copy of a stored signing certificate
+func (s *Server) SigningCertificate(id string) (ftc_client.SigningCertificate, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cert, ok := s.certs[id]
+	if !ok {
+		return ftc_client.SigningCertificate{}, false
+	}
+	return *cert, true
+}
+
+// Domain - Returns a copy of a stored domain
+func (s *Server) Domain(id string) (ftc_client.Domain, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	domain, ok := s.domains[id]
+	if !ok {
+		return ftc_client.Domain{}, false
+	}
+	return *domain, true
+}
+
+// apiError - JSON body of every error response
+type apiError struct {
+	Code    string `json:"error_code"`
+	Message string `json:"message"`
+}
+
+// httpError - Error carrying the status it is answered with
+type httpError struct {
+	status int
+	apiError
+}
+
+func (e *httpError) Error() string {
+	return e.Message
+}
+
+func badRequest(format string, args ...interface{}) *httpError {
+	return &httpError{http.StatusBadRequest, apiError{"invalid_request", fmt.Sprintf(format, args...)}}
+}
+
+func notFound(kind, id string) *httpError {
+	return &httpError{http.StatusNotFound, apiError{"not_found", fmt.Sprintf("%s %s not found", kind, id)}}
+}
+
+func conflict(format string, args ...interface{}) *httpError {
+	return &httpError{http.StatusConflict, apiError{"conflict", fmt.Sprintf(format, args...)}}
+}
+
+// route - Handles one method on a path pattern, {id} segments are passed in order
+type route struct {
+	method  string
+	pattern string
+	handler func(s *Server, r *http.Request, ids []string) (int, interface{}, *httpError)
+}
+
+var routes = []route{
+	{"GET", "/api/v1/realm", (*Server).listRealms},
+	{"POST", "/api/v1/realm", (*Server).createRealm},
+	{"GET", "/api/v1/realm/{id}", (*Server).getRealm},
+	{"PUT", "/api/v1/realm/{id}", (*Server).updateRealm},
+	{"DELETE", "/api/v1/realm/{id}", (*Server).deleteRealm},
+
+	{"GET", "/api/v1/branding", (*Server).listBrandings},
+	{"POST", "/api/v1/branding", (*Server).createBranding},
+	{"GET", "/api/v1/branding/{id}", (*Server).getBranding},
+	{"PUT", "/api/v1/branding/{id}", (*Server).updateBranding},
+	{"DELETE", "/api/v1/branding/{id}", (*Server).deleteBranding},
+
+	{"GET", "/api/v1/signing_cert", (*Server).listSigningCerts},
+	{"POST", "/api/v1/signing_cert", (*Server).createSigningCert},
+	{"GET", "/api/v1/signing_cert/{id}", (*Server).getSigningCert},
+	{"PUT", "/api/v1/signing_cert/{id}", (*Server).updateSigningCert},
+	{"DELETE", "/api/v1/signing_cert/{id}", (*Server).deleteSigningCert},
+
+	{"GET", "/api/v1/application", (*Server).listApplications},
+	{"POST", "/api/v1/application", (*Server).createApplication},
+	{"GET", "/api/v1/application/{id}", (*Server).getApplication},
+	{"PUT", "/api/v1/application/{id}", (*Server).updateApplication},
+	{"DELETE", "/api/v1/application/{id}", (*Server).deleteApplication},
+	{"PUT", "/api/v1/application/{id}/user_source", (*Server).updateApplicationUserSources},
+
+	// domain routes come first, "domain" would otherwise match a user source ID
+	{"POST", "/api/v1/usersource/domain", (*Server).createDomain},
+	{"GET", "/api/v1/usersource/domain/{id}", (*Server).getDomain},
+	{"PUT", "/api/v1/usersource/domain/{id}", (*Server).updateDomain},
+	{"DELETE", "/api/v1/usersource/domain/{id}", (*Server).deleteDomain},
+
+	{"GET", "/api/v1/usersource", (*Server).listUserSources},
+	{"POST", "/api/v1/usersource", (*Server).createUserSource},
+	{"GET", "/api/v1/usersource/{id}", (*Server).getUserSource},
+	{"PUT", "/api/v1/usersource/{id}", (*Server).updateUserSource},
+	{"DELETE", "/api/v1/usersource/{id}", (*Server).deleteUserSource},
+	{"PUT", "/api/v1/usersource/{id}/domain", (*Server).updateUserSourceDomains},
+}
+
+// match - Reports whether path fits pattern and returns the {id} segments
+func match(pattern, path string) ([]string, bool) {
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	if len(want) != len(got) {
+		return nil, false
+	}
+	var ids []string
+	for i := range want {
+		if want[i] == "{id}" {
+			ids = append(ids, got[i])
+		} else if want[i] != got[i] {
+			return nil, false
+		}
+	}
+	return ids, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := newID()
+	w.Header().Set("X-Request-Id", requestID)
+
+	if r.URL.Path == "/api/v1/login" && r.Method == "POST" {
+		s.login(w, r)
+		return
+	}
+	if ids, ok := match("/saml-idp/{id}/metadata", r.URL.Path); ok && r.Method == "GET" {
+		s.idpMetadata(w, ids[0])
+		return
+	}
+	if ids, ok := match("/oidc/{id}/.well-known/openid-configuration", r.URL.Path); ok && r.Method == "GET" {
+		s.oidcConfiguration(w, ids[0])
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if expiry, ok := s.tokens[token]; !ok || time.Now().After(expiry) {
+		writeJSON(w, http.StatusUnauthorized, apiError{"unauthorized", "invalid or expired access token"})
+		return
+	}
+
+	if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{"invalid_request", "unreadable body"})
+			return
+		}
+		s.bodies[r.Method+" "+r.URL.Path] = string(body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if len(s.failures) > 0 {
+		f := s.failures[0]
+		s.failures = s.failures[1:]
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		writeJSON(w, f.status, apiError{"injected", http.StatusText(f.status)})
+		return
+	}
+
+	pathMatched := false
+	for _, rt := range routes {
+		ids, ok := match(rt.pattern, r.URL.Path)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if rt.method != r.Method {
+			continue
+		}
+		status, body, herr := rt.handler(s, r, ids)
+		if herr != nil {
+			writeJSON(w, herr.status, herr.apiError)
+			return
+		}
+		if body == nil {
+			w.WriteHeader(status)
+			return
+		}
+		writeJSON(w, status, body)
+		return
+	}
+
+	if pathMatched {
+		writeJSON(w, http.StatusMethodNotAllowed, apiError{"method_not_allowed", r.Method + " is not allowed on " + r.URL.Path})
+		return
+	}
+	writeJSON(w, http.StatusNotFound, apiError{"not_found", "no such endpoint " + r.URL.Path})
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var auth ftc_client.AuthStruct
+	if err := json.NewDecoder(r.Body).Decode(&auth); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{"invalid_request", "malformed JSON body"})
+		return
+	}
+	if auth.ClientID != ClientID || auth.ClientSecret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, apiError{"invalid_client", "invalid client credentials"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token := newID()
+	s.tokens[token] = time.Now().Add(s.TokenTTL)
+
+	writeJSON(w, http.StatusOK, ftc_client.AuthResponse{Token: token, ExpiresIn: int(s.TokenTTL / time.Second)})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func decode(r *http.Request, v interface{}) *httpError {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return badRequest("malformed JSON body: %s", err)
+	}
+	return nil
+}
+
+// paginate - Applies the page and limit query parameters to a sorted slice
+func paginate[T any](r *http.Request, items []T) ([]T, *httpError) {
+	query := r.URL.Query()
+	page, limit := 1, len(items)
+	if v := query.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, badRequest("page must be a positive integer")
+		}
+		page = n
+	}
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, badRequest("limit must be a positive integer")
+		}
+		limit = n
+	}
+	start := (page - 1) * limit
+	if start >= len(items) {
+		return []T{}, nil
+	}
+	end := min(start+limit, len(items))
+	return items[start:end], nil
+}
+
+// sortedValues - Returns map values ordered by key so pages are stable
+func sortedValues[T any](m map[string]*T) []T {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]T, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, *m[k])
+	}
+	return values
+}
+
+// newID - Returns a random UUID shaped identifier
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// newPrefix - Returns the short random prefix FTC uses in generated URLs
+func newPrefix() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
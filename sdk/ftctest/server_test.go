@@ -0,0 +1,360 @@
+package ftctest_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+	"terraform-provider-fortitokencloud/sdk/ftctest"
+)
+
+// newTestClient - Starts a server and signs a client in to it
+func newTestClient(t *testing.T, opts ...ftc_client.ClientOption) (*ftc_client.Client, *ftctest.Server) {
+	t.Helper()
+	srv := ftctest.NewServer(t)
+	t.Cleanup(srv.Close)
+
+	host, clientID, clientSecret := srv.URL, ftctest.ClientID, ftctest.ClientSecret
+	client, err := ftc_client.NewClient(&host, &clientID, &clientSecret, opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client, srv
+}
+
+func TestLoginRejectsBadCredentials(t *testing.T) {
+	srv := ftctest.NewServer(t)
+	defer srv.Close()
+
+	host, clientID, clientSecret := srv.URL, ftctest.ClientID, "wrong"
+	_, err := ftc_client.NewClient(&host, &clientID, &clientSecret)
+	if !ftc_client.IsUnauthorized(err) {
+		t.Fatalf("NewClient with a wrong secret: got %v, want a 401 APIError", err)
+	}
+}
+
+func TestTokenTTL(t *testing.T) {
+	srv := ftctest.NewServer(t)
+	defer srv.Close()
+	// tokens expiring within the SDK's refresh window are replaced before every request
+	srv.TokenTTL = 30 * time.Second
+
+	host, clientID, clientSecret := srv.URL, ftctest.ClientID, ftctest.ClientSecret
+	client, err := ftc_client.NewClient(&host, &clientID, &clientSecret)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	token := client.Token
+	if _, err := client.GetRealmByNameWithContext(context.Background(), ftctest.DefaultRealm); err != nil {
+		t.Fatalf("GetRealmByName: %v", err)
+	}
+	if client.Token == token {
+		t.Error("token about to expire was not refreshed")
+	}
+}
+
+func TestExpireTokens(t *testing.T) {
+	client, srv := newTestClient(t)
+
+	token := client.Token
+	srv.ExpireTokens()
+	if _, err := client.GetRealmByNameWithContext(context.Background(), ftctest.DefaultRealm); err != nil {
+		t.Fatalf("GetRealmByName after the token was revoked: %v", err)
+	}
+	if client.Token == token {
+		t.Error("client kept the revoked token")
+	}
+}
+
+func TestFailNext(t *testing.T) {
+	client, srv := newTestClient(t, ftc_client.WithRetry(0, 0))
+	ctx := context.Background()
+
+	srv.FailNext(2, http.StatusServiceUnavailable, "7")
+	for i := 0; i < 2; i++ {
+		_, err := client.GetRealmByNameWithContext(ctx, ftctest.DefaultRealm)
+		var apiErr *ftc_client.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("request %d: got %v, want an injected 503", i, err)
+		}
+		if apiErr.RequestID == "" {
+			t.Error("APIError has no request ID")
+		}
+	}
+	if _, err := client.GetRealmByNameWithContext(ctx, ftctest.DefaultRealm); err != nil {
+		t.Fatalf("request after the injected failures: %v", err)
+	}
+}
+
+func TestFailNextSkipsLogin(t *testing.T) {
+	client, srv := newTestClient(t, ftc_client.WithRetry(1, time.Millisecond))
+
+	// the sign in triggered by the revoked token is not answered with the failure
+	srv.ExpireTokens()
+	srv.FailNext(1, http.StatusServiceUnavailable, "")
+	if _, err := client.GetRealmByNameWithContext(context.Background(), ftctest.DefaultRealm); err != nil {
+		t.Fatalf("GetRealmByName: %v", err)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	client, srv := newTestClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		app := map[string]interface{}{"name": fmt.Sprintf("app-%d", i), "realm_id": srv.DefaultRealmID()}
+		if _, err := client.CreateApplicationWithContext(ctx, app); err != nil {
+			t.Fatalf("CreateApplication: %v", err)
+		}
+	}
+
+	pager := client.NewApplicationsPager(ftc_client.ListOptions{Limit: 2})
+	pages := []int{}
+	seen := map[string]bool{}
+	for pager.Next(ctx) {
+		pages = append(pages, len(pager.Page()))
+		for _, app := range pager.Page() {
+			seen[app.ID] = true
+		}
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatalf("pager: %v", err)
+	}
+	if fmt.Sprint(pages) != "[2 2 1]" || len(seen) != 5 {
+		t.Errorf("page sizes = %v with %d distinct applications, want [2 2 1] with 5", pages, len(seen))
+	}
+}
+
+func TestApplicationLifecycle(t *testing.T) {
+	client, srv := newTestClient(t)
+	ctx := context.Background()
+
+	app, err := client.CreateApplicationWithContext(ctx, map[string]interface{}{"name": "portal", "realm_id": srv.DefaultRealmID()})
+	if err != nil {
+		t.Fatalf("CreateApplication: %v", err)
+	}
+	if _, ok := srv.Application(app.ID); !ok {
+		t.Fatalf("application %s not stored", app.ID)
+	}
+
+	if _, err := client.CreateApplicationWithContext(ctx, map[string]interface{}{"name": "portal", "realm_id": srv.DefaultRealmID()}); !ftc_client.IsConflict(err) {
+		t.Errorf("creating a second application named portal: got %v, want a 409", err)
+	}
+	if _, err := client.CreateApplicationWithContext(ctx, map[string]interface{}{"name": "other", "realm_id": "missing"}); err == nil {
+		t.Error("creating an application in a missing realm succeeded")
+	}
+
+	if err := client.DeleteApplicationWithContext(ctx, app.ID); err != nil {
+		t.Fatalf("DeleteApplication: %v", err)
+	}
+	if _, err := client.GetApplicationWithContext(ctx, app.ID); !ftc_client.IsNotFound(err) {
+		t.Errorf("GetApplication after delete: got %v, want a 404", err)
+	}
+}
+
+func TestDomainLifecycle(t *testing.T) {
+	client, srv := newTestClient(t)
+	ctx := context.Background()
+
+	domain, err := client.CreateDomainWithContext(ctx, map[string]interface{}{"name": "example.com", "realm_id": srv.DefaultRealmID()})
+	if err != nil {
+		t.Fatalf("CreateDomain: %v", err)
+	}
+	if _, err := client.UpdateDomainWithContext(ctx, domain.ID, map[string]interface{}{"name": "example.org"}); err != nil {
+		t.Fatalf("UpdateDomain: %v", err)
+	}
+	if stored, _ := srv.Domain(domain.ID); stored.Name != "example.org" {
+		t.Errorf("domain name = %q, want example.org", stored.Name)
+	}
+	if err := client.DeleteDomainWithContext(ctx, domain.ID); err != nil {
+		t.Fatalf("DeleteDomain: %v", err)
+	}
+	if _, ok := srv.Domain(domain.ID); ok {
+		t.Error("domain still stored after delete")
+	}
+}
+
+func TestBrandingLifecycle(t *testing.T) {
+	client, srv := newTestClient(t)
+	ctx := context.Background()
+
+	branding, err := client.CreateBrandingWithContext(ctx, map[string]interface{}{
+		"name":     "corporate",
+		"realm_id": srv.DefaultRealmID(),
+		"logo":     base64.StdEncoding.EncodeToString([]byte("logo")),
+	})
+	if err != nil {
+		t.Fatalf("CreateBranding: %v", err)
+	}
+	if _, err := client.UpdateBrandingWithContext(ctx, branding.ID, map[string]interface{}{"primary_color": "blue"}); err == nil {
+		t.Error("updating a branding with an invalid color succeeded")
+	}
+	if _, err := client.UpdateBrandingWithContext(ctx, branding.ID, map[string]interface{}{"logo": nil}); err != nil {
+		t.Fatalf("UpdateBranding: %v", err)
+	}
+	if stored, _ := srv.Branding(branding.ID); stored.Logo != "" {
+		t.Errorf("logo = %q after removing it", stored.Logo)
+	}
+
+	// a branding used by an application cannot be deleted
+	app, err := client.CreateApplicationWithContext(ctx, map[string]interface{}{"name": "portal", "realm_id": srv.DefaultRealmID(), "branding_id": branding.ID})
+	if err != nil {
+		t.Fatalf("CreateApplication: %v", err)
+	}
+	if err := client.DeleteBrandingWithContext(ctx, branding.ID); !ftc_client.IsConflict(err) {
+		t.Errorf("deleting a branding in use: got %v, want a 409", err)
+	}
+	if err := client.DeleteApplicationWithContext(ctx, app.ID); err != nil {
+		t.Fatalf("DeleteApplication: %v", err)
+	}
+	if err := client.DeleteBrandingWithContext(ctx, branding.ID); err != nil {
+		t.Fatalf("DeleteBranding: %v", err)
+	}
+	if _, err := client.GetBrandingWithContext(ctx, branding.ID); !ftc_client.IsNotFound(err) {
+		t.Errorf("GetBranding after delete: got %v, want a 404", err)
+	}
+}
+
+func TestSigningCertificateLifecycle(t *testing.T) {
+	client, srv := newTestClient(t)
+	ctx := context.Background()
+
+	cert, err := client.CreateSigningCertificateWithContext(ctx, map[string]interface{}{
+		"name": "generated", "realm_id": srv.DefaultRealmID(), "common_name": "idp.example.com", "validity_days": 30,
+	})
+	if err != nil {
+		t.Fatalf("CreateSigningCertificate: %v", err)
+	}
+	if block, _ := pem.Decode([]byte(cert.Certificate)); block == nil {
+		t.Fatalf("generated certificate is not PEM: %q", cert.Certificate)
+	}
+	if _, err := client.CreateSigningCertificateWithContext(ctx, map[string]interface{}{
+		"name": "uploaded", "realm_id": srv.DefaultRealmID(), "certificate": cert.Certificate, "private_key": "not a key",
+	}); err == nil {
+		t.Error("uploading a certificate without its key succeeded")
+	}
+	if _, err := client.UpdateSigningCertificateWithContext(ctx, cert.ID, map[string]interface{}{"common_name": "other.example.com"}); err == nil {
+		t.Error("changing the common name of a certificate succeeded")
+	}
+
+	// a certificate used by an application cannot be deleted
+	app, err := client.CreateApplicationWithContext(ctx, map[string]interface{}{
+		"name": "portal", "realm_id": srv.DefaultRealmID(), "saml_params": map[string]interface{}{"signing_cert_id": cert.ID},
+	})
+	if err != nil {
+		t.Fatalf("CreateApplication: %v", err)
+	}
+	if err := client.DeleteSigningCertificateWithContext(ctx, cert.ID); !ftc_client.IsConflict(err) {
+		t.Errorf("deleting a certificate in use: got %v, want a 409", err)
+	}
+	if err := client.DeleteApplicationWithContext(ctx, app.ID); err != nil {
+		t.Fatalf("DeleteApplication: %v", err)
+	}
+	if err := client.DeleteSigningCertificateWithContext(ctx, cert.ID); err != nil {
+		t.Fatalf("DeleteSigningCertificate: %v", err)
+	}
+	if _, ok := srv.SigningCertificate(cert.ID); ok {
+		t.Error("signing certificate still stored after delete")
+	}
+}
+
+func TestOidcApplication(t *testing.T) {
+	client, srv := newTestClient(t)
+	ctx := context.Background()
+
+	app, err := client.CreateApplicationWithContext(ctx, map[string]interface{}{
+		"name": "portal", "realm_id": srv.DefaultRealmID(), "type": 2,
+		"oidc_params": map[string]interface{}{
+			"redirect_uris": []string{"https://portal.example.com/callback"},
+			"scopes":        []string{"openid"},
+			"grant_types":   []string{"authorization_code"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateApplication: %v", err)
+	}
+	if app.OidcParams == nil || app.OidcParams.ClientID == "" || app.OidcParams.ClientSecret == "" {
+		t.Fatalf("created application has no client credentials: %+v", app.OidcParams)
+	}
+	if app.EntityID != "" {
+		t.Errorf("OIDC application has SAML entity ID %q", app.EntityID)
+	}
+
+	// the client secret is only returned on create
+	got, err := client.GetApplicationWithContext(ctx, app.ID)
+	if err != nil {
+		t.Fatalf("GetApplication: %v", err)
+	}
+	if got.OidcParams.ClientID != app.OidcParams.ClientID || got.OidcParams.ClientSecret != "" {
+		t.Errorf("read application credentials = %q/%q, want %q and no secret", got.OidcParams.ClientID, got.OidcParams.ClientSecret, app.OidcParams.ClientID)
+	}
+
+	resp, err := http.Get(app.OidcParams.DiscoveryURL)
+	if err != nil {
+		t.Fatalf("GET %s: %v", app.OidcParams.DiscoveryURL, err)
+	}
+	defer resp.Body.Close()
+	var discovery struct {
+		Issuer string `json:"issuer"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		t.Fatalf("decoding discovery document: %v", err)
+	}
+	if discovery.Issuer != app.OidcParams.Issuer {
+		t.Errorf("discovery issuer = %q, want %q", discovery.Issuer, app.OidcParams.Issuer)
+	}
+
+	if _, err := client.CreateApplicationWithContext(ctx, map[string]interface{}{
+		"name": "other", "realm_id": srv.DefaultRealmID(), "type": 3,
+	}); err == nil {
+		t.Error("creating an application of unknown type succeeded")
+	}
+}
+
+func TestIdPMetadata(t *testing.T) {
+	client, srv := newTestClient(t)
+
+	app, err := client.CreateApplicationWithContext(context.Background(), map[string]interface{}{"name": "portal", "realm_id": srv.DefaultRealmID()})
+	if err != nil {
+		t.Fatalf("CreateApplication: %v", err)
+	}
+
+	// the metadata is published without authentication
+	res, err := http.Get(app.EntityID)
+	if err != nil {
+		t.Fatalf("GET %s: %v", app.EntityID, err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d", app.EntityID, res.StatusCode)
+	}
+	block, _ := pem.Decode([]byte(srv.SigningCert()))
+	if block == nil {
+		t.Fatal("SigningCert is not PEM encoded")
+	}
+	for _, want := range []string{`entityID="` + app.EntityID + `"`, app.SsoUrl, base64.StdEncoding.EncodeToString(block.Bytes)} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metadata does not contain %q:\n%s", want, body)
+		}
+	}
+
+	res, err = http.Get(srv.URL + "/saml-idp/missing/metadata/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("metadata of a missing application: status %d, want 404", res.StatusCode)
+	}
+}
@@ -0,0 +1,132 @@
+package ftc_client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// LogSubsystem - tflog subsystem of the SDK, its level is set with TF_LOG_PROVIDER_FORTITOKENCLOUD_FTC_API
+const LogSubsystem = "ftc_api"
+
+// redactedValue - Replacement logged instead of a secret
+const redactedValue = "***"
+
+// sensitiveKeys - JSON keys whose values never reach the logs, at any nesting depth
+var sensitiveKeys = map[string]bool{
+	"client_secret": true,
+	"clientsecret":  true,
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"password":      true,
+	"private_key":   true,
+}
+
+// sensitiveJSONPattern - Fallback for bodies that are not valid JSON
+var sensitiveJSONPattern = regexp.MustCompile(`("(?:client_secret|clientsecret|access_token|refresh_token|id_token|password|private_key)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// logContext - Attaches the SDK subsystem to ctx and masks the credentials the client holds
+func (c *Client) logContext(ctx context.Context, token string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, LogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_FORTITOKENCLOUD", "FTC_API"))
+
+	secrets := []string{}
+	for _, s := range []string{token, c.Auth.ClientSecret} {
+		if s != "" {
+			secrets = append(secrets, s)
+		}
+	}
+	if len(secrets) > 0 {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, LogSubsystem, secrets...)
+		ctx = tflog.SubsystemMaskMessageStrings(ctx, LogSubsystem, secrets...)
+	}
+
+	return ctx
+}
+
+// logRequest - Logs the request body at TRACE level
+func logRequest(ctx context.Context, req *http.Request) {
+	if req.GetBody == nil {
+		return
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	b, err := io.ReadAll(body)
+	if err != nil || len(b) == 0 {
+		return
+	}
+
+	tflog.SubsystemTrace(ctx, LogSubsystem, "FTC API request body", map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.String(),
+		"body":   redactBody(b),
+	})
+}
+
+// logResponse - Logs method, URL, status, latency and request ID, plus the body at TRACE level
+func logResponse(ctx context.Context, req *http.Request, res *http.Response, body []byte, latency time.Duration, err error) {
+	fields := map[string]interface{}{
+		"method":     req.Method,
+		"url":        req.URL.String(),
+		"latency_ms": latency.Milliseconds(),
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, LogSubsystem, "FTC API request failed", fields)
+		return
+	}
+
+	fields["status"] = res.StatusCode
+	if id := res.Header.Get("X-Request-Id"); id != "" {
+		fields["request_id"] = id
+	}
+	tflog.SubsystemDebug(ctx, LogSubsystem, "FTC API request", fields)
+
+	if len(body) > 0 {
+		fields["body"] = redactBody(body)
+		tflog.SubsystemTrace(ctx, LogSubsystem, "FTC API response body", fields)
+	}
+}
+
+// redactBody - Returns body with the values of sensitive keys masked
+func redactBody(body []byte) string {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return sensitiveJSONPattern.ReplaceAllString(string(body), `${1}"`+redactedValue+`"`)
+	}
+
+	b, err := json.Marshal(redactValue(payload))
+	if err != nil {
+		return redactedValue
+	}
+	return string(b)
+}
+
+// redactValue - Walks decoded JSON and masks the values of sensitive keys
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			if sensitiveKeys[k] {
+				t[k] = redactedValue
+			} else {
+				t[k] = redactValue(child)
+			}
+		}
+	case []interface{}:
+		for i, child := range t {
+			t[i] = redactValue(child)
+		}
+	}
+	return v
+}
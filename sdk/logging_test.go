@@ -0,0 +1,121 @@
+package ftc_client_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// logEntries - Returns the log entries of the SDK subsystem written to output
+func logEntries(t *testing.T, output *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	entries, err := tflogtest.MultilineJSONDecode(output)
+	if err != nil {
+		t.Fatalf("decoding log output: %v", err)
+	}
+	subsystem := []map[string]interface{}{}
+	for _, entry := range entries {
+		if entry["@module"] == "provider."+ftc_client.LogSubsystem {
+			subsystem = append(subsystem, entry)
+		}
+	}
+	return subsystem
+}
+
+func TestLogRedaction(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_FORTITOKENCLOUD_FTC_API", "TRACE")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/login":
+			fmt.Fprint(w, `{"access_token": "token-value", "expires_in": 3600}`)
+		case "/api/v1/application":
+			w.Header().Set("X-Request-Id", "req-1")
+			fmt.Fprint(w, `{"id": "app-1", "oidc_params": {"client_id": "client", "client_secret": "generated-secret"}}`)
+		default:
+			// not valid JSON, redacted with the fallback pattern
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"password": "hunter2", "message": "bad`)
+		}
+	}))
+	defer srv.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	host, clientID, clientSecret := srv.URL, "client", "login-secret"
+	client, err := ftc_client.NewClientWithContext(ctx, &host, &clientID, &clientSecret, ftc_client.WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := client.CreateApplicationWithContext(ctx, map[string]interface{}{
+		"name":     "app",
+		"password": "app-password",
+		"users":    []interface{}{map[string]interface{}{"private_key": "key-value"}},
+	}); err != nil {
+		t.Fatalf("CreateApplication: %v", err)
+	}
+	_, _ = client.GetApplicationWithContext(ctx, "broken")
+
+	logged := output.String()
+	entries := logEntries(t, &output)
+	if len(entries) == 0 {
+		t.Fatal("no log entries written by the SDK subsystem")
+	}
+	for _, secret := range []string{"login-secret", "token-value", "generated-secret", "app-password", "key-value", "hunter2"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("log output contains %q", secret)
+		}
+	}
+
+	// the request is still identifiable
+	found := false
+	for _, entry := range entries {
+		if entry["@message"] == "FTC API request" && entry["request_id"] == "req-1" {
+			found = true
+			if entry["method"] != http.MethodPost || entry["status"] != float64(http.StatusOK) {
+				t.Errorf("request entry = %v, want POST with status 200", entry)
+			}
+			if _, ok := entry["latency_ms"]; !ok {
+				t.Errorf("request entry = %v, want latency_ms", entry)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no entry logged the create request with its request ID")
+	}
+}
+
+func TestLogLevelFromEnv(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_FORTITOKENCLOUD_FTC_API", "DEBUG")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token": "token-value", "expires_in": 3600}`)
+	}))
+	defer srv.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	host, clientID, clientSecret := srv.URL, "client", "login-secret"
+	if _, err := ftc_client.NewClientWithContext(ctx, &host, &clientID, &clientSecret); err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	entries := logEntries(t, &output)
+	if len(entries) == 0 {
+		t.Fatal("no log entries written by the SDK subsystem")
+	}
+	for _, entry := range entries {
+		if entry["@level"] != "debug" {
+			t.Errorf("entry %q logged at %s with the subsystem at DEBUG", entry["@message"], entry["@level"])
+		}
+	}
+}
@@ -22,9 +22,23 @@ type Application struct {
 	SpSloUrl      string              `json:"sp_slo_url"`
 	SpNameID      string              `json:"sp_name_id"`
 	SpSigningCert string              `json:"sp_signing_cert"`
+	OidcParams    *OidcAppParams      `json:"oidc_params,omitempty"`
 	UserSources   []UserSourceElement `json:"user_sources"`
 }
 
+type OidcAppParams struct {
+	RedirectURIs         []string `json:"redirect_uris"`
+	Scopes               []string `json:"scopes"`
+	GrantTypes           []string `json:"grant_types"`
+	AccessTokenLifetime  int      `json:"access_token_lifetime"`
+	IDTokenLifetime      int      `json:"id_token_lifetime"`
+	RefreshTokenLifetime int      `json:"refresh_token_lifetime"`
+	ClientID             string   `json:"client_id"`
+	ClientSecret         string   `json:"client_secret,omitempty"`
+	Issuer               string   `json:"issuer"`
+	DiscoveryURL         string   `json:"discovery_url"`
+}
+
 type SamlParams struct {
 	SigningCertID string `json:"signing_cert_id"`
 	SpEntityID    string `json:"sp_entity_id"`
@@ -113,3 +127,34 @@ type Realm struct {
 	Name string `json:"name"`
 	ID   string `json:"id"`
 }
+
+type Brandings struct {
+	Brandings []Branding
+}
+
+type Branding struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	RealmID         string `json:"realm_id"`
+	Title           string `json:"title"`
+	Logo            string `json:"logo"`
+	PrimaryColor    string `json:"primary_color"`
+	BackgroundColor string `json:"background_color"`
+	TextColor       string `json:"text_color"`
+	FooterText      string `json:"footer_text"`
+}
+
+type SigningCertificates struct {
+	SigningCertificates []SigningCertificate
+}
+
+type SigningCertificate struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	RealmID      string `json:"realm_id"`
+	Certificate  string `json:"certificate"`
+	Subject      string `json:"subject"`
+	SerialNumber string `json:"serial_number"`
+	NotBefore    string `json:"not_before"`
+	NotAfter     string `json:"not_after"`
+}
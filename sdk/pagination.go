@@ -0,0 +1,154 @@
+package ftc_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize - Records requested per page when ListOptions.Limit is not set
+const DefaultPageSize = 100
+
+// ListOptions - Page selection for list endpoints, pages are numbered from 1
+type ListOptions struct {
+	Page  int
+	Limit int
+}
+
+// withDefaults - Fills in the first page and the default page size
+func (o ListOptions) withDefaults() ListOptions {
+	if o.Page < 1 {
+		o.Page = 1
+	}
+	if o.Limit < 1 {
+		o.Limit = DefaultPageSize
+	}
+	return o
+}
+
+// listURL - Returns the list endpoint URL with the page and limit query parameters
+func (c *Client) listURL(apiPath string, opts ListOptions) string {
+	opts = opts.withDefaults()
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(opts.Page))
+	query.Set("limit", strconv.Itoa(opts.Limit))
+	return fmt.Sprintf("%s%s?%s", c.HostURL, apiPath, query.Encode())
+}
+
+// listPage - Envelope FTC may wrap a page of results in
+type listPage struct {
+	Results json.RawMessage `json:"results"`
+	Data    json.RawMessage `json:"data"`
+	Next    *string         `json:"next"`
+}
+
+// decodePage - Decodes a page given either as a bare array or as an envelope,
+// more reports whether the envelope announced a next page
+func decodePage(body []byte, items interface{}) (more *bool, err error) {
+	if err = json.Unmarshal(body, items); err == nil {
+		return nil, nil
+	}
+
+	page := listPage{}
+	if json.Unmarshal(body, &page) != nil {
+		return nil, err
+	}
+
+	raw := page.Results
+	if raw == nil {
+		raw = page.Data
+	}
+	if raw == nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(raw, items); err != nil {
+		return nil, err
+	}
+
+	if page.Next != nil || page.Results != nil {
+		hasNext := page.Next != nil && *page.Next != ""
+		more = &hasNext
+	}
+	return more, nil
+}
+
+// Pager - Walks a list endpoint one page at a time
+//
+//	pager := client.NewApplicationsPager(ftc_client.ListOptions{})
+//	for pager.Next(ctx) {
+//		for _, app := range pager.Page() { ... }
+//	}
+//	if err := pager.Err(); err != nil { ... }
+type Pager[T any] struct {
+	fetch func(ctx context.Context, opts ListOptions) ([]T, *bool, error)
+	id    func(T) string
+	seen  map[string]bool
+	opts  ListOptions
+	page  []T
+	done  bool
+	err   error
+}
+
+// newPager - Creates a pager starting at opts.Page, id identifies a record across pages
+func newPager[T any](opts ListOptions, id func(T) string, fetch func(ctx context.Context, opts ListOptions) ([]T, *bool, error)) *Pager[T] {
+	return &Pager[T]{fetch: fetch, id: id, seen: map[string]bool{}, opts: opts.withDefaults()}
+}
+
+// Next - Fetches the next page, returns false once every page was read or a request failed
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+
+	page, more, err := p.fetch(ctx, p.opts)
+	if err != nil {
+		p.err = err
+		p.page = nil
+		return false
+	}
+
+	// An endpoint without pagination support answers every page with the same
+	// records, stop instead of returning them twice
+	if len(page) > 0 && p.seen[p.id(page[0])] {
+		p.done = true
+		p.page = nil
+		return false
+	}
+	for _, item := range page {
+		p.seen[p.id(item)] = true
+	}
+
+	p.page = page
+	p.opts.Page++
+	switch {
+	case more != nil:
+		p.done = !*more
+	default:
+		// A short page is the last one. A page larger than the limit means the
+		// endpoint ignored pagination and returned every record at once.
+		p.done = len(page) != p.opts.Limit
+	}
+
+	return len(page) > 0
+}
+
+// Page - Returns the records of the page fetched by the last call to Next
+func (p *Pager[T]) Page() []T {
+	return p.page
+}
+
+// Err - Returns the error that stopped the pager, if any
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All - Reads the remaining pages and returns their records
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	items := make([]T, 0)
+	for p.Next(ctx) {
+		items = append(items, p.Page()...)
+	}
+	return items, p.Err()
+}
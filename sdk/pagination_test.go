@@ -0,0 +1,206 @@
+package ftc_client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// pageServer - Serves list responses for the application and user source endpoints
+// built by pages from the requested page and limit
+func pageServer(t *testing.T, pages func(page, limit int) (int, string)) *ftc_client.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/login" {
+			fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+			return
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		status, body := pages(page, limit)
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	host, clientID, clientSecret := srv.URL, "client", "secret"
+	client, err := ftc_client.NewClient(&host, &clientID, &clientSecret, ftc_client.WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// sliceServer - Pages through count records returned as bare arrays
+func sliceServer(t *testing.T, count int) *ftc_client.Client {
+	t.Helper()
+	return pageServer(t, func(page, limit int) (int, string) {
+		records := []map[string]string{}
+		for i := (page - 1) * limit; i < page*limit && i < count; i++ {
+			records = append(records, map[string]string{"id": fmt.Sprintf("app-%d", i)})
+		}
+		body, _ := json.Marshal(records)
+		return http.StatusOK, string(body)
+	})
+}
+
+func TestPagerWalksEveryPage(t *testing.T) {
+	client := sliceServer(t, 5)
+	ctx := context.Background()
+
+	tests := []struct {
+		limit int
+		pages []int
+	}{
+		{limit: 2, pages: []int{2, 2, 1}},
+		{limit: 5, pages: []int{5}},
+		{limit: 0, pages: []int{5}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("limit %d", tt.limit), func(t *testing.T) {
+			pager := client.NewApplicationsPager(ftc_client.ListOptions{Limit: tt.limit})
+			pages := []int{}
+			seen := map[string]bool{}
+			for pager.Next(ctx) {
+				pages = append(pages, len(pager.Page()))
+				for _, app := range pager.Page() {
+					if seen[app.ID] {
+						t.Errorf("application %s returned twice", app.ID)
+					}
+					seen[app.ID] = true
+				}
+			}
+			if err := pager.Err(); err != nil {
+				t.Fatalf("pager: %v", err)
+			}
+			if fmt.Sprint(pages) != fmt.Sprint(tt.pages) {
+				t.Errorf("page sizes = %v, want %v", pages, tt.pages)
+			}
+			if len(seen) != 5 {
+				t.Errorf("got %d applications, want 5", len(seen))
+			}
+		})
+	}
+}
+
+func TestPagerAll(t *testing.T) {
+	client := sliceServer(t, 3)
+	ctx := context.Background()
+
+	apps, err := client.NewApplicationsPager(ftc_client.ListOptions{Limit: 1}).All(ctx)
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(apps) != 3 {
+		t.Errorf("got %d applications, want 3", len(apps))
+	}
+
+	apps, err = client.NewApplicationsPager(ftc_client.ListOptions{Page: 2, Limit: 2}).All(ctx)
+	if err != nil {
+		t.Fatalf("All from page 2: %v", err)
+	}
+	if len(apps) != 1 {
+		t.Errorf("got %d applications from page 2, want 1", len(apps))
+	}
+}
+
+func TestPagerStopsOnError(t *testing.T) {
+	client := pageServer(t, func(page, limit int) (int, string) {
+		return http.StatusInternalServerError, ""
+	})
+	ctx := context.Background()
+
+	pager := client.NewApplicationsPager(ftc_client.ListOptions{})
+	if pager.Next(ctx) {
+		t.Fatal("Next succeeded on a failed request")
+	}
+	if pager.Page() != nil {
+		t.Errorf("Page() = %v after a failed request, want nil", pager.Page())
+	}
+	if err := pager.Err(); err == nil {
+		t.Fatal("Err() = nil after a failed request")
+	}
+	if pager.Next(ctx) {
+		t.Error("Next continued after an error")
+	}
+}
+
+func TestPagerEnvelope(t *testing.T) {
+	client := pageServer(t, func(page, limit int) (int, string) {
+		switch page {
+		case 1:
+			return http.StatusOK, `{"results": [{"id": "a"}, {"id": "b"}], "next": "?page=2"}`
+		case 2:
+			return http.StatusOK, `{"results": [{"id": "c"}], "next": null}`
+		}
+		t.Errorf("pager asked for page %d after the last one", page)
+		return http.StatusOK, `{"results": []}`
+	})
+
+	// the envelope's next link decides, a short page before it is not the end
+	sources, err := client.NewUserSourcesPager(ftc_client.ListOptions{Limit: 3}).All(context.Background())
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(sources) != 3 {
+		t.Errorf("got %d user sources, want 3", len(sources))
+	}
+}
+
+func TestPagerDataEnvelope(t *testing.T) {
+	client := pageServer(t, func(page, limit int) (int, string) {
+		if page != 1 {
+			return http.StatusOK, `{"data": []}`
+		}
+		return http.StatusOK, `{"data": [{"id": "a"}, {"id": "b"}]}`
+	})
+
+	sources, err := client.NewUserSourcesPager(ftc_client.ListOptions{Limit: 2}).All(context.Background())
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(sources) != 2 {
+		t.Errorf("got %d user sources, want 2", len(sources))
+	}
+}
+
+func TestPagerIgnoredPagination(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		// every page repeats the same full page of records
+		{name: "same page repeated", body: `[{"id": "a"}, {"id": "b"}]`, want: 2},
+		// the first page already holds more records than the limit
+		{name: "everything at once", body: `[{"id": "a"}, {"id": "b"}, {"id": "c"}]`, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			client := pageServer(t, func(int, int) (int, string) {
+				requests.Add(1)
+				return http.StatusOK, tt.body
+			})
+
+			sources, err := client.NewUserSourcesPager(ftc_client.ListOptions{Limit: 2}).All(context.Background())
+			if err != nil {
+				t.Fatalf("All: %v", err)
+			}
+			if len(sources) != tt.want {
+				t.Errorf("got %d user sources, want %d", len(sources), tt.want)
+			}
+			if n := requests.Load(); n > 2 {
+				t.Errorf("pager sent %d list requests, want at most 2", n)
+			}
+		})
+	}
+}
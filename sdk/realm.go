@@ -1,6 +1,7 @@
 package ftc_client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,9 +11,14 @@ import (
 
 var realmApiPath = "/api/v1/realm"
 
-// GetRealmByName - Returns realm with name
+// GetRealmByName - Calls GetRealmByNameWithContext with a background context
 func (c *Client) GetRealmByName(RealmName string) (*Realm, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s?name=%s", c.HostURL, realmApiPath, RealmName), nil)
+	return c.GetRealmByNameWithContext(context.Background(), RealmName)
+}
+
+// GetRealmByNameWithContext - Returns realm with name
+func (c *Client) GetRealmByNameWithContext(ctx context.Context, RealmName string) (*Realm, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s?name=%s", c.HostURL, realmApiPath, RealmName), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -36,14 +42,56 @@ func (c *Client) GetRealmByName(RealmName string) (*Realm, error) {
 	return &realm[0], nil
 }
 
-// CreateRealm - Create a new realm
+// GetRealm - Calls GetRealmWithContext with a background context
+func (c *Client) GetRealm(realmId string) (*Realm, error) {
+	return c.GetRealmWithContext(context.Background(), realmId)
+}
+
+// GetRealmWithContext - Returns specific realm
+func (c *Client) GetRealmWithContext(ctx context.Context, realmId string) (*Realm, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s/%s", c.HostURL, realmApiPath, realmId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	realm := Realm{}
+
+	err = json.Unmarshal(body, &realm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &realm, nil
+}
+
+// CreatRealm - Deprecated: misspelled, use CreateRealm
 func (c *Client) CreatRealm(realmData interface{}) (*Realm, error) {
+	return c.CreateRealmWithContext(context.Background(), realmData)
+}
+
+// CreatRealmWithContext - Deprecated: misspelled, use CreateRealmWithContext
+func (c *Client) CreatRealmWithContext(ctx context.Context, realmData interface{}) (*Realm, error) {
+	return c.CreateRealmWithContext(ctx, realmData)
+}
+
+// CreateRealm - Calls CreateRealmWithContext with a background context
+func (c *Client) CreateRealm(realmData interface{}) (*Realm, error) {
+	return c.CreateRealmWithContext(context.Background(), realmData)
+}
+
+// CreateRealmWithContext - Create a new realm
+func (c *Client) CreateRealmWithContext(ctx context.Context, realmData interface{}) (*Realm, error) {
 	rb, err := json.Marshal(realmData)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", c.HostURL, realmApiPath), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.HostURL, realmApiPath), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
@@ -62,14 +110,19 @@ func (c *Client) CreatRealm(realmData interface{}) (*Realm, error) {
 	return &realm, nil
 }
 
-// UpdateRealm - Updates an realm
+// UpdateRealm - Calls UpdateRealmWithContext with a background context
 func (c *Client) UpdateRealm(realmId string, realmData interface{}) (*Realm, error) {
+	return c.UpdateRealmWithContext(context.Background(), realmId, realmData)
+}
+
+// UpdateRealmWithContext - Updates a realm
+func (c *Client) UpdateRealmWithContext(ctx context.Context, realmId string, realmData interface{}) (*Realm, error) {
 	rb, err := json.Marshal(realmData)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s%s/%s", c.HostURL, appApiPath, realmId), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s%s/%s", c.HostURL, realmApiPath, realmId), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
@@ -88,9 +141,14 @@ func (c *Client) UpdateRealm(realmId string, realmData interface{}) (*Realm, err
 	return &realm, nil
 }
 
-// DeleteRealm - Deletes a realm
+// DeleteRealm - Calls DeleteRealmWithContext with a background context
 func (c *Client) DeleteRealm(realmId string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s%s%s", c.HostURL, realmApiPath, realmId), nil)
+	return c.DeleteRealmWithContext(context.Background(), realmId)
+}
+
+// DeleteRealmWithContext - Deletes a realm
+func (c *Client) DeleteRealmWithContext(ctx context.Context, realmId string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s%s/%s", c.HostURL, realmApiPath, realmId), nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,171 @@
+package ftc_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var signingCertApiPath = "/api/v1/signing_cert"
+
+// GetSigningCertificates - Calls GetSigningCertificatesWithContext with a background context
+func (c *Client) GetSigningCertificates() (*SigningCertificates, error) {
+	return c.GetSigningCertificatesWithContext(context.Background())
+}
+
+// GetSigningCertificatesWithContext - Returns all signing certificates, reading every page
+func (c *Client) GetSigningCertificatesWithContext(ctx context.Context) (*SigningCertificates, error) {
+	certs, err := c.NewSigningCertificatesPager(ListOptions{}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningCertificates{SigningCertificates: certs}, nil
+}
+
+// NewSigningCertificatesPager - Returns a pager over the signing certificates, starting at opts.Page
+func (c *Client) NewSigningCertificatesPager(opts ListOptions) *Pager[SigningCertificate] {
+	return newPager(opts, func(cert SigningCertificate) string { return cert.ID }, c.getSigningCertificatesPage)
+}
+
+// GetSigningCertificatesPageWithContext - Returns a single page of signing certificates
+func (c *Client) GetSigningCertificatesPageWithContext(ctx context.Context, opts ListOptions) ([]SigningCertificate, error) {
+	certs, _, err := c.getSigningCertificatesPage(ctx, opts)
+	return certs, err
+}
+
+func (c *Client) getSigningCertificatesPage(ctx context.Context, opts ListOptions) ([]SigningCertificate, *bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.listURL(signingCertApiPath, opts), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certs := []SigningCertificate{}
+	more, err := decodePage(body, &certs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return certs, more, nil
+}
+
+// GetSigningCertificate - Calls GetSigningCertificateWithContext with a background context
+func (c *Client) GetSigningCertificate(certId string) (*SigningCertificate, error) {
+	return c.GetSigningCertificateWithContext(context.Background(), certId)
+}
+
+// GetSigningCertificateWithContext - Returns specific signing certificate
+func (c *Client) GetSigningCertificateWithContext(ctx context.Context, certId string) (*SigningCertificate, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s/%s", c.HostURL, signingCertApiPath, certId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cert := SigningCertificate{}
+
+	err = json.Unmarshal(body, &cert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
+
+// CreateSigningCertificate - Calls CreateSigningCertificateWithContext with a background context
+func (c *Client) CreateSigningCertificate(certData interface{}) (*SigningCertificate, error) {
+	return c.CreateSigningCertificateWithContext(context.Background(), certData)
+}
+
+// CreateSigningCertificateWithContext - Create a new signing certificate
+func (c *Client) CreateSigningCertificateWithContext(ctx context.Context, certData interface{}) (*SigningCertificate, error) {
+	rb, err := json.Marshal(certData)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.HostURL, signingCertApiPath), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cert := SigningCertificate{}
+	err = json.Unmarshal(body, &cert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
+
+// UpdateSigningCertificate - Calls UpdateSigningCertificateWithContext with a background context
+func (c *Client) UpdateSigningCertificate(certId string, certData interface{}) (*SigningCertificate, error) {
+	return c.UpdateSigningCertificateWithContext(context.Background(), certId, certData)
+}
+
+// UpdateSigningCertificateWithContext - Updates a signing certificate
+func (c *Client) UpdateSigningCertificateWithContext(ctx context.Context, certId string, certData interface{}) (*SigningCertificate, error) {
+	rb, err := json.Marshal(certData)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s%s/%s", c.HostURL, signingCertApiPath, certId), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cert := SigningCertificate{}
+	err = json.Unmarshal(body, &cert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
+
+// DeleteSigningCertificate - Calls DeleteSigningCertificateWithContext with a background context
+func (c *Client) DeleteSigningCertificate(certId string) error {
+	return c.DeleteSigningCertificateWithContext(context.Background(), certId)
+}
+
+// DeleteSigningCertificateWithContext - Deletes a signing certificate
+func (c *Client) DeleteSigningCertificateWithContext(ctx context.Context, certId string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s%s/%s", c.HostURL, signingCertApiPath, certId), nil)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+
+	if string(body) != "" {
+		return errors.New(string(body))
+	}
+
+	return nil
+}
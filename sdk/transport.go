@@ -0,0 +1,107 @@
+package ftc_client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout - Timeout of a single HTTP request when none is configured
+const DefaultTimeout = 10 * time.Second
+
+// TransportConfig - TLS, proxy and timeout settings of the HTTP client talking to FTC
+type TransportConfig struct {
+	// CACert, ClientCert and ClientKey accept either a file path or inline PEM
+	CACert     string
+	ClientCert string
+	ClientKey  string
+	Insecure   bool
+	// Proxy overrides the HTTP_PROXY/HTTPS_PROXY environment variables when set
+	Proxy   string
+	Timeout time.Duration
+}
+
+// WithHTTPClient - Replaces the default HTTP client, e.g. with one built by NewHTTPClient
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// NewHTTPClient - Builds an HTTP client with a transport configured from cfg
+func NewHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = t.Clone()
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// lab setups only, the provider warns when this is turned on
+		InsecureSkipVerify: cfg.Insecure, //nolint:gosec
+	}
+
+	if cfg.CACert != "" {
+		pem, err := readPEM(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificate: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM encoded certificate found in CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, fmt.Errorf("client certificate and client key must be set together")
+		}
+		certPEM, err := readPEM(cfg.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("reading client certificate: %w", err)
+		}
+		keyPEM, err := readPEM(cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("reading client key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("parsing proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// readPEM - Returns value itself when it holds PEM data, otherwise the content of the file it names
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
@@ -0,0 +1,221 @@
+package ftc_client_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ftc_client "terraform-provider-fortitokencloud/sdk"
+)
+
+// testClientCert - Returns a self-signed client certificate and its key, both PEM encoded
+func testClientCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "terraform"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+// serverCAPEM - Returns the certificate of a TLS test server, PEM encoded
+func serverCAPEM(srv *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+}
+
+func writeTemp(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, "ok")
+}
+
+func TestNewHTTPClientServerVerification(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(okHandler))
+	defer srv.Close()
+	ca := serverCAPEM(srv)
+
+	tests := []struct {
+		name    string
+		cfg     ftc_client.TransportConfig
+		wantErr string
+	}{
+		{name: "unknown CA rejected", wantErr: "certificate"},
+		{name: "inline CA", cfg: ftc_client.TransportConfig{CACert: ca}},
+		{name: "CA file", cfg: ftc_client.TransportConfig{CACert: writeTemp(t, "ca.pem", ca)}},
+		{name: "insecure", cfg: ftc_client.TransportConfig{Insecure: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient, err := ftc_client.NewHTTPClient(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewHTTPClient: %v", err)
+			}
+			res, err := httpClient.Get(srv.URL)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %v, want an error mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GET: %v", err)
+			}
+			res.Body.Close()
+		})
+	}
+}
+
+func TestNewHTTPClientMutualTLS(t *testing.T) {
+	cert, key := testClientCert(t)
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM([]byte(cert))
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(okHandler))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	srv.StartTLS()
+	defer srv.Close()
+	ca := serverCAPEM(srv)
+
+	tests := []struct {
+		name    string
+		cfg     ftc_client.TransportConfig
+		wantErr bool
+	}{
+		{name: "no client certificate", cfg: ftc_client.TransportConfig{CACert: ca}, wantErr: true},
+		{name: "inline certificate", cfg: ftc_client.TransportConfig{CACert: ca, ClientCert: cert, ClientKey: key}},
+		{name: "certificate files", cfg: ftc_client.TransportConfig{
+			CACert:     ca,
+			ClientCert: writeTemp(t, "client.pem", cert),
+			ClientKey:  writeTemp(t, "client.key", key),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient, err := ftc_client.NewHTTPClient(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewHTTPClient: %v", err)
+			}
+			res, err := httpClient.Get(srv.URL)
+			if tt.wantErr {
+				if err == nil {
+					res.Body.Close()
+					t.Fatal("GET succeeded without a client certificate")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GET: %v", err)
+			}
+			res.Body.Close()
+		})
+	}
+}
+
+func TestNewHTTPClientInvalidConfig(t *testing.T) {
+	cert, key := testClientCert(t)
+	otherCert, _ := testClientCert(t)
+
+	tests := []struct {
+		name    string
+		cfg     ftc_client.TransportConfig
+		wantErr string
+	}{
+		{name: "CA without certificate", cfg: ftc_client.TransportConfig{CACert: writeTemp(t, "ca.pem", "not a certificate")}, wantErr: "no PEM encoded certificate"},
+		{name: "missing CA file", cfg: ftc_client.TransportConfig{CACert: filepath.Join(t.TempDir(), "missing.pem")}, wantErr: "reading CA certificate"},
+		{name: "certificate without key", cfg: ftc_client.TransportConfig{ClientCert: cert}, wantErr: "must be set together"},
+		{name: "key without certificate", cfg: ftc_client.TransportConfig{ClientKey: key}, wantErr: "must be set together"},
+		{name: "key of another certificate", cfg: ftc_client.TransportConfig{ClientCert: otherCert, ClientKey: key}, wantErr: "loading client certificate"},
+		{name: "invalid proxy", cfg: ftc_client.TransportConfig{Proxy: "http://proxy:port"}, wantErr: "parsing proxy URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ftc_client.NewHTTPClient(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got %v, want an error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	proxied := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a forward proxy receives the absolute URL
+		proxied <- r.URL.String()
+		fmt.Fprint(w, "ok")
+	}))
+	defer proxy.Close()
+
+	httpClient, err := ftc_client.NewHTTPClient(ftc_client.TransportConfig{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewHTTPClient: %v", err)
+	}
+	res, err := httpClient.Get("http://ftc.example.com/api/v1/realm")
+	if err != nil {
+		t.Fatalf("GET through the proxy: %v", err)
+	}
+	res.Body.Close()
+	if got := <-proxied; got != "http://ftc.example.com/api/v1/realm" {
+		t.Errorf("proxy received %q, want the FTC URL", got)
+	}
+}
+
+func TestNewHTTPClientTimeout(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{timeout: 0, want: ftc_client.DefaultTimeout},
+		{timeout: -time.Second, want: ftc_client.DefaultTimeout},
+		{timeout: 90 * time.Second, want: 90 * time.Second},
+	}
+	for _, tt := range tests {
+		httpClient, err := ftc_client.NewHTTPClient(ftc_client.TransportConfig{Timeout: tt.timeout})
+		if err != nil {
+			t.Fatalf("NewHTTPClient: %v", err)
+		}
+		if httpClient.Timeout != tt.want {
+			t.Errorf("timeout %s: client timeout = %s, want %s", tt.timeout, httpClient.Timeout, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package ftc_client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,30 +11,60 @@ import (
 
 var usApiPath = "/api/v1/usersource"
 
-// GetUserSources - Returns all user sources
+// GetUserSources - Calls GetUserSourcesWithContext with a background context
 func (c *Client) GetUserSources() (*UserSources, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", c.HostURL, usApiPath), nil)
+	return c.GetUserSourcesWithContext(context.Background())
+}
+
+// GetUserSourcesWithContext - Returns all user sources, reading every page
+func (c *Client) GetUserSourcesWithContext(ctx context.Context) (*UserSources, error) {
+	usersources, err := c.NewUserSourcesPager(ListOptions{}).All(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	return &UserSources{UserSources: usersources}, nil
+}
+
+// NewUserSourcesPager - Returns a pager over the user sources, starting at opts.Page
+func (c *Client) NewUserSourcesPager(opts ListOptions) *Pager[UserSource] {
+	return newPager(opts, func(us UserSource) string { return us.ID }, c.getUserSourcesPage)
+}
+
+// GetUserSourcesPageWithContext - Returns a single page of user sources
+func (c *Client) GetUserSourcesPageWithContext(ctx context.Context, opts ListOptions) ([]UserSource, error) {
+	usersources, _, err := c.getUserSourcesPage(ctx, opts)
+	return usersources, err
+}
+
+func (c *Client) getUserSourcesPage(ctx context.Context, opts ListOptions) ([]UserSource, *bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.listURL(usApiPath, opts), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	body, err := c.doRequest(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	usersources := UserSources{}
-	err = json.Unmarshal(body, &usersources.UserSources)
+	usersources := []UserSource{}
+	more, err := decodePage(body, &usersources)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return &usersources, nil
+	return usersources, more, nil
 }
 
-// GetApplication - Returns specific application
+// GetUserSource - Calls GetUserSourceWithContext with a background context
 func (c *Client) GetUserSource(UserSourceId string) (*UserSource, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.HostURL, usApiPath, UserSourceId), nil)
+	return c.GetUserSourceWithContext(context.Background(), UserSourceId)
+}
+
+// GetUserSourceWithContext - Returns specific user source
+func (c *Client) GetUserSourceWithContext(ctx context.Context, UserSourceId string) (*UserSource, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s/%s", c.HostURL, usApiPath, UserSourceId), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -53,14 +84,19 @@ func (c *Client) GetUserSource(UserSourceId string) (*UserSource, error) {
 	return &usersource, nil
 }
 
-// CreateApplication - Create a new application
+// CreateUserSource - Calls CreateUserSourceWithContext with a background context
 func (c *Client) CreateUserSource(usData interface{}) (*UserSource, error) {
+	return c.CreateUserSourceWithContext(context.Background(), usData)
+}
+
+// CreateUserSourceWithContext - Create a new user source
+func (c *Client) CreateUserSourceWithContext(ctx context.Context, usData interface{}) (*UserSource, error) {
 	rb, err := json.Marshal(usData)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", c.HostURL, usApiPath), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.HostURL, usApiPath), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
@@ -79,14 +115,19 @@ func (c *Client) CreateUserSource(usData interface{}) (*UserSource, error) {
 	return &usersource, nil
 }
 
-// UpdateApplication - Updates an application
+// UpdateUserSource - Calls UpdateUserSourceWithContext with a background context
 func (c *Client) UpdateUserSource(UserSourceId string, userSourceData interface{}) (*UserSource, error) {
+	return c.UpdateUserSourceWithContext(context.Background(), UserSourceId, userSourceData)
+}
+
+// UpdateUserSourceWithContext - Updates a user source
+func (c *Client) UpdateUserSourceWithContext(ctx context.Context, UserSourceId string, userSourceData interface{}) (*UserSource, error) {
 	rb, err := json.Marshal(userSourceData)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s%s/%s", c.HostURL, usApiPath, UserSourceId), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s%s/%s", c.HostURL, usApiPath, UserSourceId), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
@@ -105,9 +146,14 @@ func (c *Client) UpdateUserSource(UserSourceId string, userSourceData interface{
 	return &usersource, nil
 }
 
-// DeleteApplication - Deletes an application
+// DeleteUserSource - Calls DeleteUserSourceWithContext with a background context
 func (c *Client) DeleteUserSource(UserSourceId string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s%s/%s", c.HostURL, usApiPath, UserSourceId), nil)
+	return c.DeleteUserSourceWithContext(context.Background(), UserSourceId)
+}
+
+// DeleteUserSourceWithContext - Deletes a user source
+func (c *Client) DeleteUserSourceWithContext(ctx context.Context, UserSourceId string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s%s/%s", c.HostURL, usApiPath, UserSourceId), nil)
 	if err != nil {
 		return err
 	}
@@ -124,8 +170,13 @@ func (c *Client) DeleteUserSource(UserSourceId string) error {
 	return nil
 }
 
-// UpdateApplicationUserSource - Updates an application's user sources
+// UpdateUserSourceDomains - Calls UpdateUserSourceDomainsWithContext with a background context
 func (c *Client) UpdateUserSourceDomains(UserSourceId string, domainList map[string][]string) (*[]UserSourceDomainMapping, error) {
+	return c.UpdateUserSourceDomainsWithContext(context.Background(), UserSourceId, domainList)
+}
+
+// UpdateUserSourceDomainsWithContext - Updates a user source's domains
+func (c *Client) UpdateUserSourceDomainsWithContext(ctx context.Context, UserSourceId string, domainList map[string][]string) (*[]UserSourceDomainMapping, error) {
 	// when array is empty, json marshal will convert it to null, need to use make
 	if len(domainList["domain_ids"]) == 0 {
 		domainList["domain_ids"] = make([]string, 0)
@@ -135,7 +186,7 @@ func (c *Client) UpdateUserSourceDomains(UserSourceId string, domainList map[str
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s%s/%s/domain", c.HostURL, usApiPath, UserSourceId), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s%s/%s/domain", c.HostURL, usApiPath, UserSourceId), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
@@ -155,8 +206,14 @@ func (c *Client) UpdateUserSourceDomains(UserSourceId string, domainList map[str
 	return &usersourcedomainmapping, nil
 }
 
+// GetDomain - Calls GetDomainWithContext with a background context
 func (c *Client) GetDomain(DomainId string) (*Domain, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s/domain/%s", c.HostURL, usApiPath, DomainId), nil)
+	return c.GetDomainWithContext(context.Background(), DomainId)
+}
+
+// GetDomainWithContext - Returns specific domain
+func (c *Client) GetDomainWithContext(ctx context.Context, DomainId string) (*Domain, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s/domain/%s", c.HostURL, usApiPath, DomainId), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -176,12 +233,18 @@ func (c *Client) GetDomain(DomainId string) (*Domain, error) {
 	return &domain, nil
 }
 
+// CreateDomain - Calls CreateDomainWithContext with a background context
 func (c *Client) CreateDomain(domainData interface{}) (*Domain, error) {
+	return c.CreateDomainWithContext(context.Background(), domainData)
+}
+
+// CreateDomainWithContext - Create a new domain
+func (c *Client) CreateDomainWithContext(ctx context.Context, domainData interface{}) (*Domain, error) {
 	rb, err := json.Marshal(domainData)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s/domain", c.HostURL, usApiPath), strings.NewReader(string(rb)))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s/domain", c.HostURL, usApiPath), strings.NewReader(string(rb)))
 	if err != nil {
 		return nil, err
 	}
@@ -200,8 +263,14 @@ func (c *Client) CreateDomain(domainData interface{}) (*Domain, error) {
 	return &domain, nil
 }
 
+// DeleteDomain - Calls DeleteDomainWithContext with a background context
 func (c *Client) DeleteDomain(DomainId string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s%s/domain/%s", c.HostURL, usApiPath, DomainId), nil)
+	return c.DeleteDomainWithContext(context.Background(), DomainId)
+}
+
+// DeleteDomainWithContext - Deletes a domain
+func (c *Client) DeleteDomainWithContext(ctx context.Context, DomainId string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s%s/domain/%s", c.HostURL, usApiPath, DomainId), nil)
 	if err != nil {
 		return err
 	}
@@ -215,12 +284,18 @@ func (c *Client) DeleteDomain(DomainId string) error {
 	return nil
 }
 
+// UpdateDomain - Calls UpdateDomainWithContext with a background context
 func (c *Client) UpdateDomain(DomainId string, domainData interface{}) (*Domain, error) {
+	return c.UpdateDomainWithContext(context.Background(), DomainId, domainData)
+}
+
+// UpdateDomainWithContext - Updates a domain
+func (c *Client) UpdateDomainWithContext(ctx context.Context, DomainId string, domainData interface{}) (*Domain, error) {
 	reqBody, err := json.Marshal(domainData)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s%s/domain/%s", c.HostURL, usApiPath, DomainId), strings.NewReader(string(reqBody)))
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s%s/domain/%s", c.HostURL, usApiPath, DomainId), strings.NewReader(string(reqBody)))
 	if err != nil {
 		return nil, err
 	}